@@ -0,0 +1,229 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// trigger metadata limits enforced by discord
+const (
+	MaxAutoModerationKeywords          = 1000
+	MaxAutoModerationKeywordLength     = 60
+	MaxAutoModerationRegexPatterns     = 10
+	MaxAutoModerationRegexLength       = 260
+	MaxAutoModerationAllowList         = 100
+	MaxAutoModerationPresetAllowList   = 1000
+	MaxAutoModerationMentionTotalLimit = 50
+	MaxAutoModerationCustomMessage     = 150
+	MaxAutoModerationTimeoutSeconds    = 2419200 // 4 weeks
+)
+
+// AutoModerationRuleParams is the body for creating or modifying a rule.
+// Zero values are left out so it can be used for partial PATCH updates.
+type AutoModerationRuleParams struct {
+	Name            string                               `json:"name,omitempty"`
+	EventType       int                                  `json:"event_type,omitempty"`
+	TriggerType     int                                  `json:"trigger_type,omitempty"`
+	TriggerMetadata *types.AutoModerationTriggerMetadata `json:"trigger_metadata,omitempty"`
+	Actions         []types.AutoModerationAction         `json:"actions,omitempty"`
+	Enabled         *bool                                `json:"enabled,omitempty"`
+	ExemptRoles     []string                             `json:"exempt_roles,omitempty"`
+	ExemptChannels  []string                             `json:"exempt_channels,omitempty"`
+}
+
+func (c *Client) ListAutoModerationRules(guildID string) ([]types.AutoModerationRule, error) {
+	endpoint := fmt.Sprintf("/guilds/%s/auto-moderation/rules", guildID)
+	resp, err := c.sendRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list auto moderation rules, status code: %d", resp.StatusCode)
+	}
+
+	var rules []types.AutoModerationRule
+	if err := json.NewDecoder(resp.Body).Decode(&rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (c *Client) GetAutoModerationRule(guildID, ruleID string) (*types.AutoModerationRule, error) {
+	endpoint := fmt.Sprintf("/guilds/%s/auto-moderation/rules/%s", guildID, ruleID)
+	resp, err := c.sendRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get auto moderation rule, status code: %d", resp.StatusCode)
+	}
+
+	var rule types.AutoModerationRule
+	if err := json.NewDecoder(resp.Body).Decode(&rule); err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (c *Client) CreateAutoModerationRule(guildID string, params AutoModerationRuleParams) (*types.AutoModerationRule, error) {
+	if params.Name == "" || params.EventType == 0 || params.TriggerType == 0 {
+		return nil, fmt.Errorf("auto moderation rule needs a name, event type and trigger type")
+	}
+	if len(params.Actions) == 0 {
+		return nil, fmt.Errorf("auto moderation rule needs at least one action")
+	}
+	if err := validateAutoModerationParams(params); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("/guilds/%s/auto-moderation/rules", guildID)
+	resp, err := c.sendRequest("POST", endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to create auto moderation rule, status code: %d", resp.StatusCode)
+	}
+
+	var rule types.AutoModerationRule
+	if err := json.NewDecoder(resp.Body).Decode(&rule); err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// ModifyAutoModerationRule updates an existing rule. The trigger type of a rule
+// can't be changed, so params.TriggerType is only used to validate the metadata.
+func (c *Client) ModifyAutoModerationRule(guildID, ruleID string, params AutoModerationRuleParams) (*types.AutoModerationRule, error) {
+	if err := validateAutoModerationParams(params); err != nil {
+		return nil, err
+	}
+
+	body := params
+	body.TriggerType = 0
+
+	endpoint := fmt.Sprintf("/guilds/%s/auto-moderation/rules/%s", guildID, ruleID)
+	resp, err := c.sendRequest("PATCH", endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to modify auto moderation rule, status code: %d", resp.StatusCode)
+	}
+
+	var rule types.AutoModerationRule
+	if err := json.NewDecoder(resp.Body).Decode(&rule); err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (c *Client) DeleteAutoModerationRule(guildID, ruleID string) error {
+	endpoint := fmt.Sprintf("/guilds/%s/auto-moderation/rules/%s", guildID, ruleID)
+	resp, err := c.sendRequest("DELETE", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete auto moderation rule, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func validateAutoModerationParams(params AutoModerationRuleParams) error {
+	if params.TriggerMetadata != nil {
+		if err := ValidateAutoModerationTriggerMetadata(params.TriggerType, *params.TriggerMetadata); err != nil {
+			return err
+		}
+	}
+
+	for _, action := range params.Actions {
+		if err := ValidateAutoModerationAction(action); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateAutoModerationTriggerMetadata checks metadata against discord's limits
+// for the given trigger type. A trigger type of 0 checks the most lenient limits.
+func ValidateAutoModerationTriggerMetadata(triggerType int, metadata types.AutoModerationTriggerMetadata) error {
+	if len(metadata.KeywordFilter) > MaxAutoModerationKeywords {
+		return fmt.Errorf("keyword_filter has %d keywords, max is %d", len(metadata.KeywordFilter), MaxAutoModerationKeywords)
+	}
+	for _, keyword := range metadata.KeywordFilter {
+		if utf8.RuneCountInString(keyword) > MaxAutoModerationKeywordLength {
+			return fmt.Errorf("keyword %q is longer than %d characters", keyword, MaxAutoModerationKeywordLength)
+		}
+	}
+
+	if len(metadata.RegexPatterns) > MaxAutoModerationRegexPatterns {
+		return fmt.Errorf("regex_patterns has %d patterns, max is %d", len(metadata.RegexPatterns), MaxAutoModerationRegexPatterns)
+	}
+	for _, pattern := range metadata.RegexPatterns {
+		if utf8.RuneCountInString(pattern) > MaxAutoModerationRegexLength {
+			return fmt.Errorf("regex pattern %q is longer than %d characters", pattern, MaxAutoModerationRegexLength)
+		}
+	}
+
+	maxAllowList := MaxAutoModerationAllowList
+	if triggerType == types.AutoModerationTriggerTypeKeywordPreset || triggerType == 0 {
+		maxAllowList = MaxAutoModerationPresetAllowList
+	}
+	if len(metadata.AllowList) > maxAllowList {
+		return fmt.Errorf("allow_list has %d entries, max is %d", len(metadata.AllowList), maxAllowList)
+	}
+	for _, allowed := range metadata.AllowList {
+		if utf8.RuneCountInString(allowed) > MaxAutoModerationKeywordLength {
+			return fmt.Errorf("allow_list entry %q is longer than %d characters", allowed, MaxAutoModerationKeywordLength)
+		}
+	}
+
+	if metadata.MentionTotalLimit < 0 || metadata.MentionTotalLimit > MaxAutoModerationMentionTotalLimit {
+		return fmt.Errorf("mention_total_limit must be between 0 and %d", MaxAutoModerationMentionTotalLimit)
+	}
+
+	return nil
+}
+
+func ValidateAutoModerationAction(action types.AutoModerationAction) error {
+	switch action.Type {
+	case types.AutoModerationActionTypeBlockMessage:
+		if utf8.RuneCountInString(action.Metadata.CustomMessage) > MaxAutoModerationCustomMessage {
+			return fmt.Errorf("custom_message is longer than %d characters", MaxAutoModerationCustomMessage)
+		}
+	case types.AutoModerationActionTypeSendAlertMessage:
+		if action.Metadata.ChannelID == "" {
+			return fmt.Errorf("send alert message action needs a channel_id")
+		}
+	case types.AutoModerationActionTypeTimeout:
+		if action.Metadata.DurationSeconds <= 0 || action.Metadata.DurationSeconds > MaxAutoModerationTimeoutSeconds {
+			return fmt.Errorf("timeout duration must be between 1 and %d seconds", MaxAutoModerationTimeoutSeconds)
+		}
+	case types.AutoModerationActionTypeBlockMemberInteraction:
+	default:
+		return fmt.Errorf("unknown auto moderation action type: %d", action.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// application command limits enforced by discord
+const (
+	MaxCommandNameLength        = 32
+	MaxCommandDescriptionLength = 100
+	MaxCommandOptions           = 25
+	MaxCommandChoices           = 25
+	MaxCommandChoiceNameLength  = 100
+	MaxCommandChoiceValueLength = 100
+	MaxCommandOptionLength      = 6000
+	MaxCommandTotalLength       = 4000
+)
+
+var commandNameRegex = regexp.MustCompile(`^[-_'\p{L}\p{N}\p{Devanagari}\p{Thai}]{1,32}$`)
+
+// ValidateApplicationCommand checks a command against discord's schema rules so that
+// mistakes show up before registration instead of as a 400 from the API.
+func ValidateApplicationCommand(cmd types.ApplicationCommand) error {
+	commandType := cmd.Type
+	if commandType == 0 {
+		commandType = types.ApplicationCommandTypeChatInput
+	}
+
+	switch commandType {
+	case types.ApplicationCommandTypeChatInput:
+		if err := validateCommandName(cmd.Name); err != nil {
+			return fmt.Errorf("command %q: %w", cmd.Name, err)
+		}
+		for locale, name := range cmd.NameLocalizations {
+			if err := validateCommandName(name); err != nil {
+				return fmt.Errorf("command %q: %s name: %w", cmd.Name, locale, err)
+			}
+		}
+		if err := validateDescription(cmd.Description); err != nil {
+			return fmt.Errorf("command %q: %w", cmd.Name, err)
+		}
+		for locale, description := range cmd.DescriptionLocalizations {
+			if err := validateDescription(description); err != nil {
+				return fmt.Errorf("command %q: %s description: %w", cmd.Name, locale, err)
+			}
+		}
+		if err := validateOptions(cmd.Options, 0); err != nil {
+			return fmt.Errorf("command %q: %w", cmd.Name, err)
+		}
+	case types.ApplicationCommandTypeUser, types.ApplicationCommandTypeMessage:
+		if n := utf8.RuneCountInString(cmd.Name); n == 0 || n > MaxCommandNameLength {
+			return fmt.Errorf("command %q: name must be 1-%d characters", cmd.Name, MaxCommandNameLength)
+		}
+		if cmd.Description != "" || len(cmd.Options) > 0 {
+			return fmt.Errorf("command %q: context menu commands can't have a description or options", cmd.Name)
+		}
+	default:
+		return fmt.Errorf("command %q: unknown command type %d", cmd.Name, cmd.Type)
+	}
+
+	if total := commandLength(cmd); total > MaxCommandTotalLength {
+		return fmt.Errorf("command %q: names, descriptions and choice values add up to %d characters, max is %d", cmd.Name, total, MaxCommandTotalLength)
+	}
+
+	return nil
+}
+
+func validateCommandName(name string) error {
+	if !commandNameRegex.MatchString(name) {
+		return fmt.Errorf("name %q must be 1-%d letters, numbers, - _ or '", name, MaxCommandNameLength)
+	}
+	if strings.ToLower(name) != name {
+		return fmt.Errorf("name %q must be lowercase", name)
+	}
+	return nil
+}
+
+func validateDescription(description string) error {
+	if n := utf8.RuneCountInString(description); n == 0 || n > MaxCommandDescriptionLength {
+		return fmt.Errorf("description must be 1-%d characters", MaxCommandDescriptionLength)
+	}
+	return nil
+}
+
+// depth is 0 for the command itself, 1 inside a subcommand group or subcommand
+// and 2 inside a subcommand of a group.
+func validateOptions(options []types.ApplicationCommandOption, depth int) error {
+	if len(options) > MaxCommandOptions {
+		return fmt.Errorf("has %d options, max is %d", len(options), MaxCommandOptions)
+	}
+
+	seen := make(map[string]bool, len(options))
+	subcommands, seenOptional := 0, false
+	for _, option := range options {
+		if err := validateCommandName(option.Name); err != nil {
+			return fmt.Errorf("option: %w", err)
+		}
+		if seen[option.Name] {
+			return fmt.Errorf("duplicate option %q", option.Name)
+		}
+		seen[option.Name] = true
+
+		if err := validateOption(option, depth); err != nil {
+			return fmt.Errorf("option %q: %w", option.Name, err)
+		}
+
+		switch option.Type {
+		case types.ApplicationCommandOptionTypeSubCommand, types.ApplicationCommandOptionTypeSubCommandGroup:
+			subcommands++
+		default:
+			if option.Required && seenOptional {
+				return fmt.Errorf("required option %q must come before optional options", option.Name)
+			}
+			if !option.Required {
+				seenOptional = true
+			}
+		}
+	}
+
+	if subcommands > 0 && subcommands != len(options) {
+		return fmt.Errorf("subcommands can't be mixed with other options")
+	}
+
+	return nil
+}
+
+func validateOption(option types.ApplicationCommandOption, depth int) error {
+	if err := validateDescription(option.Description); err != nil {
+		return err
+	}
+	for locale, name := range option.NameLocalizations {
+		if err := validateCommandName(name); err != nil {
+			return fmt.Errorf("%s name: %w", locale, err)
+		}
+	}
+	for locale, description := range option.DescriptionLocalizations {
+		if err := validateDescription(description); err != nil {
+			return fmt.Errorf("%s description: %w", locale, err)
+		}
+	}
+
+	switch option.Type {
+	case types.ApplicationCommandOptionTypeSubCommandGroup:
+		if depth != 0 {
+			return fmt.Errorf("subcommand groups can only be used at the top level")
+		}
+		if len(option.Options) == 0 {
+			return fmt.Errorf("subcommand group needs at least one subcommand")
+		}
+		for _, sub := range option.Options {
+			if sub.Type != types.ApplicationCommandOptionTypeSubCommand {
+				return fmt.Errorf("subcommand groups can only contain subcommands")
+			}
+		}
+		return validateOptions(option.Options, depth+1)
+	case types.ApplicationCommandOptionTypeSubCommand:
+		if depth > 1 {
+			return fmt.Errorf("subcommands can't be nested this deep")
+		}
+		for _, sub := range option.Options {
+			if sub.Type == types.ApplicationCommandOptionTypeSubCommand || sub.Type == types.ApplicationCommandOptionTypeSubCommandGroup {
+				return fmt.Errorf("subcommands can't contain subcommands")
+			}
+		}
+		return validateOptions(option.Options, depth+1)
+	}
+
+	if len(option.Options) > 0 {
+		return fmt.Errorf("only subcommands and subcommand groups can have nested options")
+	}
+
+	if len(option.Choices) > MaxCommandChoices {
+		return fmt.Errorf("has %d choices, max is %d", len(option.Choices), MaxCommandChoices)
+	}
+	if len(option.Choices) > 0 && option.Autocomplete {
+		return fmt.Errorf("autocomplete can't be used together with choices")
+	}
+	for _, choice := range option.Choices {
+		if n := utf8.RuneCountInString(choice.Name); n == 0 || n > MaxCommandChoiceNameLength {
+			return fmt.Errorf("choice name %q must be 1-%d characters", choice.Name, MaxCommandChoiceNameLength)
+		}
+		if value, ok := choice.Value.(string); ok && utf8.RuneCountInString(value) > MaxCommandChoiceValueLength {
+			return fmt.Errorf("choice value %q is longer than %d characters", value, MaxCommandChoiceValueLength)
+		}
+	}
+
+	if option.Autocomplete {
+		switch option.Type {
+		case types.ApplicationCommandOptionTypeString, types.ApplicationCommandOptionTypeInteger, types.ApplicationCommandOptionTypeNumber:
+		default:
+			return fmt.Errorf("autocomplete is only supported for string, integer and number options")
+		}
+	}
+
+	if option.MinValue != nil || option.MaxValue != nil {
+		if option.Type != types.ApplicationCommandOptionTypeInteger && option.Type != types.ApplicationCommandOptionTypeNumber {
+			return fmt.Errorf("min_value and max_value are only supported for integer and number options")
+		}
+		if option.MinValue != nil && option.MaxValue != nil && *option.MinValue > *option.MaxValue {
+			return fmt.Errorf("min_value is greater than max_value")
+		}
+	}
+
+	if option.MinLength != nil || option.MaxLength != nil {
+		if option.Type != types.ApplicationCommandOptionTypeString {
+			return fmt.Errorf("min_length and max_length are only supported for string options")
+		}
+		if option.MinLength != nil && (*option.MinLength < 0 || *option.MinLength > MaxCommandOptionLength) {
+			return fmt.Errorf("min_length must be between 0 and %d", MaxCommandOptionLength)
+		}
+		if option.MaxLength != nil && (*option.MaxLength < 1 || *option.MaxLength > MaxCommandOptionLength) {
+			return fmt.Errorf("max_length must be between 1 and %d", MaxCommandOptionLength)
+		}
+		if option.MinLength != nil && option.MaxLength != nil && *option.MinLength > *option.MaxLength {
+			return fmt.Errorf("min_length is greater than max_length")
+		}
+	}
+
+	if len(option.ChannelTypes) > 0 && option.Type != types.ApplicationCommandOptionTypeChannel {
+		return fmt.Errorf("channel_types is only supported for channel options")
+	}
+
+	return nil
+}
+
+// commandLength counts the characters discord includes in its 4000 character limit
+func commandLength(cmd types.ApplicationCommand) int {
+	total := utf8.RuneCountInString(cmd.Name) + utf8.RuneCountInString(cmd.Description)
+
+	var count func(options []types.ApplicationCommandOption)
+	count = func(options []types.ApplicationCommandOption) {
+		for _, option := range options {
+			total += utf8.RuneCountInString(option.Name) + utf8.RuneCountInString(option.Description)
+			for _, choice := range option.Choices {
+				total += utf8.RuneCountInString(choice.Name)
+				if value, ok := choice.Value.(string); ok {
+					total += utf8.RuneCountInString(value)
+				}
+			}
+			count(option.Options)
+		}
+	}
+	count(cmd.Options)
+
+	return total
+}
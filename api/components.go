@@ -0,0 +1,185 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// component limits enforced by discord
+const (
+	MaxActionRows         = 5  // top-level rows in a message without components V2
+	MaxActionRowButtons   = 5
+	MaxComponentsV2       = 40 // all components of a message, nested ones included
+	MaxSectionTextDisplay = 3
+	MaxMediaGalleryItems  = 10
+	MaxModalComponents    = 5
+)
+
+// ValidateMessageComponents checks the components of a message against
+// discord's nesting rules. v2 tells whether the message sets
+// MessageFlagIsComponentsV2; without it only action rows are allowed at the top.
+func ValidateMessageComponents(components []types.MessageComponent, v2 bool) error {
+	if !v2 {
+		if len(components) > MaxActionRows {
+			return fmt.Errorf("a message can have at most %d action rows", MaxActionRows)
+		}
+		for i, component := range components {
+			if err := validateActionRow(component, false); err != nil {
+				return fmt.Errorf("component %d: %w", i, err)
+			}
+		}
+		return nil
+	}
+
+	for i, component := range components {
+		switch component.(type) {
+		case types.ActionRowComponent, types.SectionComponent, types.TextDisplayComponent,
+			types.MediaGalleryComponent, types.FileComponent, types.SeparatorComponent, types.ContainerComponent:
+		default:
+			return fmt.Errorf("component %d: type %d can't be used at the top level", i, component.ComponentType())
+		}
+		if err := validateLayoutComponent(component); err != nil {
+			return fmt.Errorf("component %d: %w", i, err)
+		}
+	}
+
+	if total := countComponents(components); total > MaxComponentsV2 {
+		return fmt.Errorf("message has %d components, max is %d", total, MaxComponentsV2)
+	}
+	return nil
+}
+
+// ValidateModalComponents checks the components of a modal. Inputs go in
+// labels (or in action rows, the older layout) and text displays may sit between them.
+func ValidateModalComponents(components []types.MessageComponent) error {
+	if len(components) == 0 || len(components) > MaxModalComponents {
+		return fmt.Errorf("a modal needs 1-%d components", MaxModalComponents)
+	}
+
+	for i, component := range components {
+		switch c := component.(type) {
+		case types.LabelComponent:
+			switch c.Component.(type) {
+			case types.TextInputComponent, types.SelectMenuComponent:
+			case nil:
+				return fmt.Errorf("component %d: label has no component", i)
+			default:
+				return fmt.Errorf("component %d: labels can only hold text inputs and selects", i)
+			}
+		case types.ActionRowComponent:
+			if len(c.Components) != 1 {
+				return fmt.Errorf("component %d: modal action rows hold exactly one text input", i)
+			}
+			if _, ok := c.Components[0].(types.TextInputComponent); !ok {
+				return fmt.Errorf("component %d: modal action rows hold exactly one text input", i)
+			}
+		case types.TextDisplayComponent:
+		default:
+			return fmt.Errorf("component %d: type %d can't be used in a modal", i, component.ComponentType())
+		}
+	}
+	return nil
+}
+
+func validateLayoutComponent(component types.MessageComponent) error {
+	switch c := component.(type) {
+	case types.ActionRowComponent:
+		return validateActionRow(c, true)
+	case types.SectionComponent:
+		if len(c.Components) == 0 || len(c.Components) > MaxSectionTextDisplay {
+			return fmt.Errorf("sections need 1-%d text displays", MaxSectionTextDisplay)
+		}
+		for _, child := range c.Components {
+			if _, ok := child.(types.TextDisplayComponent); !ok {
+				return fmt.Errorf("sections can only hold text displays, got type %d", child.ComponentType())
+			}
+		}
+		switch c.Accessory.(type) {
+		case types.ThumbnailComponent, types.ButtonComponent:
+		case nil:
+			return fmt.Errorf("sections need an accessory")
+		default:
+			return fmt.Errorf("section accessory must be a thumbnail or button, got type %d", c.Accessory.ComponentType())
+		}
+	case types.MediaGalleryComponent:
+		if len(c.Items) == 0 || len(c.Items) > MaxMediaGalleryItems {
+			return fmt.Errorf("media galleries need 1-%d items", MaxMediaGalleryItems)
+		}
+	case types.FileComponent:
+		if !strings.HasPrefix(c.File.URL, "attachment://") {
+			return fmt.Errorf("file components must reference an upload with attachment://")
+		}
+	case types.ContainerComponent:
+		for i, child := range c.Components {
+			switch child.(type) {
+			case types.ActionRowComponent, types.SectionComponent, types.TextDisplayComponent,
+				types.MediaGalleryComponent, types.FileComponent, types.SeparatorComponent:
+			default:
+				return fmt.Errorf("container component %d: type %d can't be used in a container", i, child.ComponentType())
+			}
+			if err := validateLayoutComponent(child); err != nil {
+				return fmt.Errorf("container component %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateActionRow checks that a row holds up to 5 buttons or a single select
+func validateActionRow(component types.MessageComponent, v2 bool) error {
+	row, ok := component.(types.ActionRowComponent)
+	if !ok {
+		if v2 {
+			return fmt.Errorf("expected an action row, got type %d", component.ComponentType())
+		}
+		return fmt.Errorf("type %d needs MessageFlagIsComponentsV2 outside an action row", component.ComponentType())
+	}
+	if len(row.Components) == 0 {
+		return fmt.Errorf("action rows can't be empty")
+	}
+
+	buttons, selects := 0, 0
+	for _, child := range row.Components {
+		switch child.(type) {
+		case types.ButtonComponent:
+			buttons++
+		case types.SelectMenuComponent:
+			selects++
+		default:
+			return fmt.Errorf("action rows can only hold buttons and selects, got type %d", child.ComponentType())
+		}
+	}
+
+	if selects > 0 && (selects > 1 || buttons > 0) {
+		return fmt.Errorf("a select menu must be alone in its action row")
+	}
+	if buttons > MaxActionRowButtons {
+		return fmt.Errorf("action rows hold at most %d buttons", MaxActionRowButtons)
+	}
+	return nil
+}
+
+func countComponents(components []types.MessageComponent) int {
+	total := 0
+	for _, component := range components {
+		total++
+		switch c := component.(type) {
+		case types.ActionRowComponent:
+			total += countComponents(c.Components)
+		case types.ContainerComponent:
+			total += countComponents(c.Components)
+		case types.SectionComponent:
+			total += countComponents(c.Components)
+			if c.Accessory != nil {
+				total++
+			}
+		case types.LabelComponent:
+			if c.Component != nil {
+				total++
+			}
+		}
+	}
+	return total
+}
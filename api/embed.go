@@ -0,0 +1,122 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// embed limits enforced by discord
+const (
+	MaxEmbedTitleLength       = 256
+	MaxEmbedDescriptionLength = 4096
+	MaxEmbedFields            = 25
+	MaxEmbedFieldNameLength   = 256
+	MaxEmbedFieldValueLength  = 1024
+	MaxEmbedFooterLength      = 2048
+	MaxEmbedAuthorLength      = 256
+	MaxEmbedTotalLength       = 6000 // across all embeds of a message
+	MaxEmbedsPerMessage       = 10
+	MaxMessageLength          = 2000
+)
+
+// EmbedLimitError lists every limit an embed (or a message's embeds) exceeds
+type EmbedLimitError struct {
+	Violations []string
+}
+
+func (e *EmbedLimitError) Error() string {
+	return "embed exceeds limits: " + strings.Join(e.Violations, "; ")
+}
+
+func (e *EmbedLimitError) add(format string, args ...interface{}) {
+	e.Violations = append(e.Violations, fmt.Sprintf(format, args...))
+}
+
+// ValidateEmbed checks a single embed against discord's limits and returns an
+// *EmbedLimitError with all violations, not just the first.
+func ValidateEmbed(embed types.Embed) error {
+	err := &EmbedLimitError{}
+	checkEmbed(err, embed, "")
+
+	if total := EmbedLength(embed); total > MaxEmbedTotalLength {
+		err.add("total length is %d, max is %d", total, MaxEmbedTotalLength)
+	}
+
+	if len(err.Violations) > 0 {
+		return err
+	}
+	return nil
+}
+
+// ValidateEmbeds checks the embeds of one message, including the combined
+// length and the number of embeds.
+func ValidateEmbeds(embeds []*types.Embed) error {
+	err := &EmbedLimitError{}
+	if len(embeds) > MaxEmbedsPerMessage {
+		err.add("a message can have at most %d embeds, got %d", MaxEmbedsPerMessage, len(embeds))
+	}
+
+	total := 0
+	for i, embed := range embeds {
+		if embed == nil {
+			continue
+		}
+		checkEmbed(err, *embed, fmt.Sprintf("embed %d: ", i))
+		total += EmbedLength(*embed)
+	}
+	if total > MaxEmbedTotalLength {
+		err.add("combined length is %d, max is %d", total, MaxEmbedTotalLength)
+	}
+
+	if len(err.Violations) > 0 {
+		return err
+	}
+	return nil
+}
+
+func checkEmbed(err *EmbedLimitError, embed types.Embed, prefix string) {
+	checkLength := func(name, value string, max int) {
+		if n := utf8.RuneCountInString(value); n > max {
+			err.add("%s%s is %d characters, max is %d", prefix, name, n, max)
+		}
+	}
+
+	checkLength("title", embed.Title, MaxEmbedTitleLength)
+	checkLength("description", embed.Description, MaxEmbedDescriptionLength)
+	if embed.Footer != nil {
+		checkLength("footer", embed.Footer.Text, MaxEmbedFooterLength)
+	}
+	if embed.Author != nil {
+		checkLength("author name", embed.Author.Name, MaxEmbedAuthorLength)
+	}
+
+	if len(embed.Fields) > MaxEmbedFields {
+		err.add("%shas %d fields, max is %d", prefix, len(embed.Fields), MaxEmbedFields)
+	}
+	for i, field := range embed.Fields {
+		if field.Name == "" || field.Value == "" {
+			err.add("%sfield %d needs a name and a value", prefix, i)
+		}
+		checkLength(fmt.Sprintf("field %d name", i), field.Name, MaxEmbedFieldNameLength)
+		checkLength(fmt.Sprintf("field %d value", i), field.Value, MaxEmbedFieldValueLength)
+	}
+}
+
+// EmbedLength returns the characters that count towards the 6000 limit: the
+// title, description, field names and values, footer text and author name.
+func EmbedLength(embed types.Embed) int {
+	total := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+	for _, field := range embed.Fields {
+		total += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+	if embed.Footer != nil {
+		total += utf8.RuneCountInString(embed.Footer.Text)
+	}
+	if embed.Author != nil {
+		total += utf8.RuneCountInString(embed.Author.Name)
+	}
+	return total
+}
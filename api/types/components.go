@@ -0,0 +1,258 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MessageComponents is a list of components that decodes each entry into its
+// concrete struct (ActionRowComponent, ButtonComponent, ...) based on its type.
+type MessageComponents []MessageComponent
+
+func (c *MessageComponents) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*c = nil
+		return nil
+	}
+
+	components := make(MessageComponents, 0, len(raw))
+	for _, entry := range raw {
+		component, err := UnmarshalComponent(entry)
+		if err != nil {
+			return err
+		}
+		components = append(components, component)
+	}
+
+	*c = components
+	return nil
+}
+
+// UnmarshalComponent decodes a single component. Types this package doesn't
+// know are returned as an UnknownComponent.
+func UnmarshalComponent(data []byte) (MessageComponent, error) {
+	var header struct {
+		Type int `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	var component MessageComponent
+	var err error
+	switch header.Type {
+	case ComponentTypeActionRow:
+		var row ActionRowComponent
+		err = json.Unmarshal(data, &row)
+		component = row
+	case ComponentTypeButton:
+		var button ButtonComponent
+		err = json.Unmarshal(data, &button)
+		component = button
+	case ComponentTypeSelectMenu, ComponentTypeUserSelect, ComponentTypeRoleSelect,
+		ComponentTypeMentionableSelect, ComponentTypeChannelSelect:
+		var menu SelectMenuComponent
+		err = json.Unmarshal(data, &menu)
+		component = menu
+	case ComponentTypeTextInput:
+		var input TextInputComponent
+		err = json.Unmarshal(data, &input)
+		component = input
+	case ComponentTypeSection:
+		var section SectionComponent
+		err = json.Unmarshal(data, &section)
+		component = section
+	case ComponentTypeTextDisplay:
+		var text TextDisplayComponent
+		err = json.Unmarshal(data, &text)
+		component = text
+	case ComponentTypeThumbnail:
+		var thumbnail ThumbnailComponent
+		err = json.Unmarshal(data, &thumbnail)
+		component = thumbnail
+	case ComponentTypeMediaGallery:
+		var gallery MediaGalleryComponent
+		err = json.Unmarshal(data, &gallery)
+		component = gallery
+	case ComponentTypeFile:
+		var file FileComponent
+		err = json.Unmarshal(data, &file)
+		component = file
+	case ComponentTypeSeparator:
+		var separator SeparatorComponent
+		err = json.Unmarshal(data, &separator)
+		component = separator
+	case ComponentTypeContainer:
+		var container ContainerComponent
+		err = json.Unmarshal(data, &container)
+		component = container
+	case ComponentTypeLabel:
+		var label LabelComponent
+		err = json.Unmarshal(data, &label)
+		component = label
+	default:
+		component = UnknownComponent{Type: header.Type, Raw: append(json.RawMessage(nil), data...)}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode component of type %d: %w", header.Type, err)
+	}
+	return component, nil
+}
+
+// UnknownComponent keeps a component of a type this package doesn't support
+// yet. It's sent back unchanged when re-encoded.
+type UnknownComponent struct {
+	Type int
+	Raw  json.RawMessage
+}
+
+func (c UnknownComponent) MarshalJSON() ([]byte, error) {
+	return c.Raw, nil
+}
+
+// The layout components below are part of Components V2. Messages using them
+// must set MessageFlagIsComponentsV2, which disables content and embeds.
+
+// SectionComponent shows 1-3 text displays next to an accessory (a thumbnail or button)
+type SectionComponent struct {
+	Type       int               `json:"type"`
+	ID         int               `json:"id,omitempty"`
+	Components MessageComponents `json:"components"`
+	Accessory  MessageComponent  `json:"accessory"`
+}
+
+func (c *SectionComponent) UnmarshalJSON(data []byte) error {
+	type section SectionComponent
+	var raw struct {
+		section
+		Accessory json.RawMessage `json:"accessory"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*c = SectionComponent(raw.section)
+	if len(raw.Accessory) > 0 && string(raw.Accessory) != "null" {
+		accessory, err := UnmarshalComponent(raw.Accessory)
+		if err != nil {
+			return err
+		}
+		c.Accessory = accessory
+	}
+	return nil
+}
+
+// TextDisplayComponent shows markdown text
+type TextDisplayComponent struct {
+	Type    int    `json:"type"`
+	ID      int    `json:"id,omitempty"`
+	Content string `json:"content"`
+}
+
+// UnfurledMediaItem references media by URL, either http(s) or attachment://filename
+type UnfurledMediaItem struct {
+	URL          string `json:"url"`
+	ProxyURL     string `json:"proxy_url,omitempty"`
+	Height       int    `json:"height,omitempty"`
+	Width        int    `json:"width,omitempty"`
+	ContentType  string `json:"content_type,omitempty"`
+	AttachmentID string `json:"attachment_id,omitempty"`
+}
+
+// ThumbnailComponent is a small image, only allowed as a section accessory
+type ThumbnailComponent struct {
+	Type        int               `json:"type"`
+	ID          int               `json:"id,omitempty"`
+	Media       UnfurledMediaItem `json:"media"`
+	Description string            `json:"description,omitempty"`
+	Spoiler     bool              `json:"spoiler,omitempty"`
+}
+
+// MediaGalleryComponent shows 1-10 images or videos in a grid
+type MediaGalleryComponent struct {
+	Type  int                `json:"type"`
+	ID    int                `json:"id,omitempty"`
+	Items []MediaGalleryItem `json:"items"`
+}
+
+type MediaGalleryItem struct {
+	Media       UnfurledMediaItem `json:"media"`
+	Description string            `json:"description,omitempty"`
+	Spoiler     bool              `json:"spoiler,omitempty"`
+}
+
+// FileComponent shows an uploaded file. The URL must use attachment://filename.
+type FileComponent struct {
+	Type    int               `json:"type"`
+	ID      int               `json:"id,omitempty"`
+	File    UnfurledMediaItem `json:"file"`
+	Spoiler bool              `json:"spoiler,omitempty"`
+	Name    string            `json:"name,omitempty"`
+	Size    int               `json:"size,omitempty"`
+}
+
+// SeparatorComponent adds vertical padding, optionally with a divider line
+type SeparatorComponent struct {
+	Type    int   `json:"type"`
+	ID      int   `json:"id,omitempty"`
+	Divider *bool `json:"divider,omitempty"`
+	Spacing int   `json:"spacing,omitempty"`
+}
+
+// ContainerComponent groups components in a box with an optional accent color
+type ContainerComponent struct {
+	Type        int               `json:"type"`
+	ID          int               `json:"id,omitempty"`
+	Components  MessageComponents `json:"components"`
+	AccentColor *int              `json:"accent_color,omitempty"`
+	Spoiler     bool              `json:"spoiler,omitempty"`
+}
+
+// LabelComponent wraps a modal input (text input or select) with a label and description
+type LabelComponent struct {
+	Type        int              `json:"type"`
+	ID          int              `json:"id,omitempty"`
+	Label       string           `json:"label"`
+	Description string           `json:"description,omitempty"`
+	Component   MessageComponent `json:"component"`
+}
+
+func (c *LabelComponent) UnmarshalJSON(data []byte) error {
+	type label LabelComponent
+	var raw struct {
+		label
+		Component json.RawMessage `json:"component"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*c = LabelComponent(raw.label)
+	if len(raw.Component) > 0 && string(raw.Component) != "null" {
+		component, err := UnmarshalComponent(raw.Component)
+		if err != nil {
+			return err
+		}
+		c.Component = component
+	}
+	return nil
+}
+
+func (c ActionRowComponent) ComponentType() int    { return ComponentTypeActionRow }
+func (c ButtonComponent) ComponentType() int       { return ComponentTypeButton }
+func (c SelectMenuComponent) ComponentType() int   { return c.Type }
+func (c TextInputComponent) ComponentType() int    { return ComponentTypeTextInput }
+func (c SectionComponent) ComponentType() int      { return ComponentTypeSection }
+func (c TextDisplayComponent) ComponentType() int  { return ComponentTypeTextDisplay }
+func (c ThumbnailComponent) ComponentType() int    { return ComponentTypeThumbnail }
+func (c MediaGalleryComponent) ComponentType() int { return ComponentTypeMediaGallery }
+func (c FileComponent) ComponentType() int         { return ComponentTypeFile }
+func (c SeparatorComponent) ComponentType() int    { return ComponentTypeSeparator }
+func (c ContainerComponent) ComponentType() int    { return ComponentTypeContainer }
+func (c LabelComponent) ComponentType() int        { return ComponentTypeLabel }
+func (c UnknownComponent) ComponentType() int      { return c.Type }
@@ -0,0 +1,132 @@
+// Code generated by internal/genevents; DO NOT EDIT.
+
+package types
+
+// Event is implemented by every gateway event struct
+type Event interface {
+	EventName() string
+}
+
+func (MessageCreateEvent) EventName() string { return "MESSAGE_CREATE" }
+
+func (MessageUpdateEvent) EventName() string { return "MESSAGE_UPDATE" }
+
+func (MessageDeleteEvent) EventName() string { return "MESSAGE_DELETE" }
+
+func (MessageDeleteBulkEvent) EventName() string { return "MESSAGE_DELETE_BULK" }
+
+func (MessageReactionAddEvent) EventName() string { return "MESSAGE_REACTION_ADD" }
+
+func (MessageReactionRemoveEvent) EventName() string { return "MESSAGE_REACTION_REMOVE" }
+
+func (MessageReactionRemoveAllEvent) EventName() string { return "MESSAGE_REACTION_REMOVE_ALL" }
+
+func (MessageReactionRemoveEmojiEvent) EventName() string { return "MESSAGE_REACTION_REMOVE_EMOJI" }
+
+func (GuildCreateEvent) EventName() string { return "GUILD_CREATE" }
+
+func (GuildUpdateEvent) EventName() string { return "GUILD_UPDATE" }
+
+func (GuildDeleteEvent) EventName() string { return "GUILD_DELETE" }
+
+func (GuildMemberAddEvent) EventName() string { return "GUILD_MEMBER_ADD" }
+
+func (GuildMemberUpdateEvent) EventName() string { return "GUILD_MEMBER_UPDATE" }
+
+func (GuildMemberRemoveEvent) EventName() string { return "GUILD_MEMBER_REMOVE" }
+
+func (GuildRoleCreateEvent) EventName() string { return "GUILD_ROLE_CREATE" }
+
+func (GuildRoleUpdateEvent) EventName() string { return "GUILD_ROLE_UPDATE" }
+
+func (GuildRoleDeleteEvent) EventName() string { return "GUILD_ROLE_DELETE" }
+
+func (ChannelCreateEvent) EventName() string { return "CHANNEL_CREATE" }
+
+func (ChannelUpdateEvent) EventName() string { return "CHANNEL_UPDATE" }
+
+func (ChannelDeleteEvent) EventName() string { return "CHANNEL_DELETE" }
+
+func (ChannelPinsUpdateEvent) EventName() string { return "CHANNEL_PINS_UPDATE" }
+
+func (ThreadCreateEvent) EventName() string { return "THREAD_CREATE" }
+
+func (ThreadUpdateEvent) EventName() string { return "THREAD_UPDATE" }
+
+func (ThreadDeleteEvent) EventName() string { return "THREAD_DELETE" }
+
+func (ThreadListSyncEvent) EventName() string { return "THREAD_LIST_SYNC" }
+
+func (ThreadMemberUpdateEvent) EventName() string { return "THREAD_MEMBER_UPDATE" }
+
+func (VoiceStateUpdateEvent) EventName() string { return "VOICE_STATE_UPDATE" }
+
+func (VoiceServerUpdateEvent) EventName() string { return "VOICE_SERVER_UPDATE" }
+
+func (PresenceUpdateEvent) EventName() string { return "PRESENCE_UPDATE" }
+
+func (UserUpdateEvent) EventName() string { return "USER_UPDATE" }
+
+func (ReadyEvent) EventName() string { return "READY" }
+
+func (ApplicationCommandCreateEvent) EventName() string { return "APPLICATION_COMMAND_CREATE" }
+
+func (ApplicationCommandUpdateEvent) EventName() string { return "APPLICATION_COMMAND_UPDATE" }
+
+func (ApplicationCommandDeleteEvent) EventName() string { return "APPLICATION_COMMAND_DELETE" }
+
+func (InteractionCreateEvent) EventName() string { return "INTERACTION_CREATE" }
+
+func (InviteCreateEvent) EventName() string { return "INVITE_CREATE" }
+
+func (InviteDeleteEvent) EventName() string { return "INVITE_DELETE" }
+
+func (StageInstanceCreateEvent) EventName() string { return "STAGE_INSTANCE_CREATE" }
+
+func (StageInstanceUpdateEvent) EventName() string { return "STAGE_INSTANCE_UPDATE" }
+
+func (StageInstanceDeleteEvent) EventName() string { return "STAGE_INSTANCE_DELETE" }
+
+func (GuildScheduledEventCreateEvent) EventName() string { return "GUILD_SCHEDULED_EVENT_CREATE" }
+
+func (GuildScheduledEventUpdateEvent) EventName() string { return "GUILD_SCHEDULED_EVENT_UPDATE" }
+
+func (GuildScheduledEventDeleteEvent) EventName() string { return "GUILD_SCHEDULED_EVENT_DELETE" }
+
+func (GuildScheduledEventUserAddEvent) EventName() string { return "GUILD_SCHEDULED_EVENT_USER_ADD" }
+
+func (GuildScheduledEventUserRemoveEvent) EventName() string {
+	return "GUILD_SCHEDULED_EVENT_USER_REMOVE"
+}
+
+func (AutoModerationRuleCreateEvent) EventName() string { return "AUTO_MODERATION_RULE_CREATE" }
+
+func (AutoModerationRuleUpdateEvent) EventName() string { return "AUTO_MODERATION_RULE_UPDATE" }
+
+func (AutoModerationRuleDeleteEvent) EventName() string { return "AUTO_MODERATION_RULE_DELETE" }
+
+func (AutoModerationActionExecutionEvent) EventName() string {
+	return "AUTO_MODERATION_ACTION_EXECUTION"
+}
+
+func (TypingStartEvent) EventName() string { return "TYPING_START" }
+
+func (WebhooksUpdateEvent) EventName() string { return "WEBHOOKS_UPDATE" }
+
+func (IntegrationCreateEvent) EventName() string { return "INTEGRATION_CREATE" }
+
+func (IntegrationUpdateEvent) EventName() string { return "INTEGRATION_UPDATE" }
+
+func (IntegrationDeleteEvent) EventName() string { return "INTEGRATION_DELETE" }
+
+func (EntitlementCreateEvent) EventName() string { return "ENTITLEMENT_CREATE" }
+
+func (EntitlementUpdateEvent) EventName() string { return "ENTITLEMENT_UPDATE" }
+
+func (EntitlementDeleteEvent) EventName() string { return "ENTITLEMENT_DELETE" }
+
+func (GuildJoinRequestCreateEvent) EventName() string { return "GUILD_JOIN_REQUEST_CREATE" }
+
+func (GuildJoinRequestUpdateEvent) EventName() string { return "GUILD_JOIN_REQUEST_UPDATE" }
+
+func (GuildJoinRequestDeleteEvent) EventName() string { return "GUILD_JOIN_REQUEST_DELETE" }
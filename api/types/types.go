@@ -60,21 +60,34 @@ type User struct {
 
 // Message struct
 type Message struct {
-	ID                string       `json:"id"`
-	ChannelID         string       `json:"channel_id"`
-	Content           string       `json:"content"`
-	Timestamp         string       `json:"timestamp"`
-	EditedTimestamp   string       `json:"edited_timestamp,omitempty"`
-	Author            User         `json:"author"`
-	Attachments       []Attachment `json:"attachments"`
-	Embeds            []Embed      `json:"embeds"`
-	Reactions         []Reaction   `json:"reactions"`
-	MentionedUsers    []string     `json:"mention_user_ids"`
-	MentionedRoles    []string     `json:"mention_role_ids"`
-	MentionedChannels []string     `json:"mention_channel_ids"`
-	MentionEveryone   bool         `json:"mention_everyone"`
-	Pinned            bool         `json:"pinned"`
-	TTS               bool         `json:"tts"`
+	ID                string            `json:"id"`
+	ChannelID         string            `json:"channel_id"`
+	GuildID           string            `json:"guild_id,omitempty"`
+	Content           string            `json:"content"`
+	Timestamp         string            `json:"timestamp"`
+	EditedTimestamp   string            `json:"edited_timestamp,omitempty"`
+	Author            User              `json:"author"`
+	Member            *GuildMember      `json:"member,omitempty"` // without User, only in guild MESSAGE_CREATE events
+	Mentions          []User            `json:"mentions,omitempty"`
+	Attachments       []Attachment      `json:"attachments"`
+	Embeds            []Embed           `json:"embeds"`
+	Reactions         []Reaction        `json:"reactions"`
+	MentionedUsers    []string          `json:"mention_user_ids"`
+	MentionedRoles    []string          `json:"mention_role_ids"`
+	MentionedChannels []string          `json:"mention_channel_ids"`
+	MentionEveryone   bool              `json:"mention_everyone"`
+	Pinned            bool              `json:"pinned"`
+	TTS               bool              `json:"tts"`
+	Components        MessageComponents `json:"components,omitempty"`
+	MessageReference  *MessageReference `json:"message_reference,omitempty"`
+}
+
+// MessageReference points at the message a reply answers
+type MessageReference struct {
+	MessageID       string `json:"message_id,omitempty"`
+	ChannelID       string `json:"channel_id,omitempty"`
+	GuildID         string `json:"guild_id,omitempty"`
+	FailIfNotExists *bool  `json:"fail_if_not_exists,omitempty"`
 }
 
 // Attachment struct
@@ -245,18 +258,18 @@ type Webhook struct {
 
 // WebhookMessage represents a message that can be sent via a webhook (used for interaction followups)
 type WebhookMessage struct {
-	Content         string             `json:"content,omitempty"`
-	Username        string             `json:"username,omitempty"`
-	AvatarURL       string             `json:"avatar_url,omitempty"`
-	TTS             bool               `json:"tts,omitempty"`
-	Embeds          []*Embed           `json:"embeds,omitempty"`
-	AllowedMentions *AllowedMentions   `json:"allowed_mentions,omitempty"`
-	Components      []MessageComponent `json:"components,omitempty"`
-	Files           []*File            `json:"-"`
-	PayloadJSON     string             `json:"payload_json,omitempty"`
-	Attachments     []*Attachment      `json:"attachments,omitempty"`
-	Flags           int                `json:"flags,omitempty"`
-	ThreadName      string             `json:"thread_name,omitempty"`
+	Content         string            `json:"content,omitempty"`
+	Username        string            `json:"username,omitempty"`
+	AvatarURL       string            `json:"avatar_url,omitempty"`
+	TTS             bool              `json:"tts,omitempty"`
+	Embeds          []*Embed          `json:"embeds,omitempty"`
+	AllowedMentions *AllowedMentions  `json:"allowed_mentions,omitempty"`
+	Components      MessageComponents `json:"components,omitempty"`
+	Files           []*File           `json:"-"`
+	PayloadJSON     string            `json:"payload_json,omitempty"`
+	Attachments     []*Attachment     `json:"attachments,omitempty"`
+	Flags           int               `json:"flags,omitempty"`
+	ThreadName      string            `json:"thread_name,omitempty"`
 }
 
 // booboo
@@ -269,7 +282,7 @@ type File struct {
 // GuildMember struct
 type GuildMember struct {
 	User         User     `json:"user"`
-	Nickname     string   `json:"nickname,omitempty"`
+	Nickname     string   `json:"nick,omitempty"`
 	Roles        []string `json:"roles"`
 	JoinedAt     string   `json:"joined_at"`
 	PremiumSince string   `json:"premium_since,omitempty"`
@@ -376,33 +389,85 @@ type Application struct {
 
 // ApplicationCommand struct
 type ApplicationCommand struct {
-	ID                string                     `json:"id"`
-	Type              int                        `json:"type,omitempty"`
-	ApplicationID     string                     `json:"application_id"`
-	GuildID           string                     `json:"guild_id,omitempty"`
-	Name              string                     `json:"name"`
-	Description       string                     `json:"description"`
-	Options           []ApplicationCommandOption `json:"options,omitempty"`
-	DefaultPermission bool                       `json:"default_permission,omitempty"`
-	Version           string                     `json:"version"`
+	ID                       string                     `json:"id"`
+	Type                     int                        `json:"type,omitempty"`
+	ApplicationID            string                     `json:"application_id"`
+	GuildID                  string                     `json:"guild_id,omitempty"`
+	Name                     string                     `json:"name"`
+	NameLocalizations        map[string]string          `json:"name_localizations,omitempty"`
+	Description              string                     `json:"description"`
+	DescriptionLocalizations map[string]string          `json:"description_localizations,omitempty"`
+	Options                  []ApplicationCommandOption `json:"options,omitempty"`
+	DefaultMemberPermissions *string                    `json:"default_member_permissions,omitempty"`
+	NSFW                     bool                       `json:"nsfw,omitempty"`
+	Contexts                 []int                      `json:"contexts,omitempty"`
+	IntegrationTypes         []int                      `json:"integration_types,omitempty"`
+	Version                  string                     `json:"version"`
+
+	// Deprecated: use DefaultMemberPermissions instead
+	DefaultPermission bool `json:"default_permission,omitempty"`
 }
 
 // ApplicationCommandOption struct
 type ApplicationCommandOption struct {
-	Type        int                              `json:"type"`
-	Name        string                           `json:"name"`
-	Description string                           `json:"description"`
-	Required    bool                             `json:"required,omitempty"`
-	Choices     []ApplicationCommandOptionChoice `json:"choices,omitempty"`
-	Options     []ApplicationCommandOption       `json:"options,omitempty"`
+	Type                     int                              `json:"type"`
+	Name                     string                           `json:"name"`
+	NameLocalizations        map[string]string                `json:"name_localizations,omitempty"`
+	Description              string                           `json:"description"`
+	DescriptionLocalizations map[string]string                `json:"description_localizations,omitempty"`
+	Required                 bool                             `json:"required,omitempty"`
+	Choices                  []ApplicationCommandOptionChoice `json:"choices,omitempty"`
+	Options                  []ApplicationCommandOption       `json:"options,omitempty"`
+	ChannelTypes             []int                            `json:"channel_types,omitempty"`
+	MinValue                 *float64                         `json:"min_value,omitempty"`
+	MaxValue                 *float64                         `json:"max_value,omitempty"`
+	MinLength                *int                             `json:"min_length,omitempty"`
+	MaxLength                *int                             `json:"max_length,omitempty"`
+	Autocomplete             bool                             `json:"autocomplete,omitempty"`
 }
 
 // ApplicationCommandOptionChoice struct
 type ApplicationCommandOptionChoice struct {
-	Name  string      `json:"name"`
-	Value interface{} `json:"value"`
+	Name              string            `json:"name"`
+	NameLocalizations map[string]string `json:"name_localizations,omitempty"`
+	Value             interface{}       `json:"value"`
+}
+
+// InteractionContextType represents where a command can be used
+const (
+	InteractionContextTypeGuild          = 0
+	InteractionContextTypeBotDM          = 1
+	InteractionContextTypePrivateChannel = 2
+)
+
+// ApplicationIntegrationType represents how an app is installed
+const (
+	ApplicationIntegrationTypeGuildInstall = 0
+	ApplicationIntegrationTypeUserInstall  = 1
+)
+
+// GuildApplicationCommandPermissions struct
+type GuildApplicationCommandPermissions struct {
+	ID            string                          `json:"id"`
+	ApplicationID string                          `json:"application_id"`
+	GuildID       string                          `json:"guild_id"`
+	Permissions   []ApplicationCommandPermissions `json:"permissions"`
 }
 
+// ApplicationCommandPermissions struct
+type ApplicationCommandPermissions struct {
+	ID         string `json:"id"`
+	Type       int    `json:"type"`
+	Permission bool   `json:"permission"`
+}
+
+// ApplicationCommandPermissionType represents what a command permission applies to
+const (
+	ApplicationCommandPermissionTypeRole    = 1
+	ApplicationCommandPermissionTypeUser    = 2
+	ApplicationCommandPermissionTypeChannel = 3
+)
+
 // StageInstance struct
 type StageInstance struct {
 	ID                   string `json:"id"`
@@ -430,11 +495,12 @@ type AutoModerationRule struct {
 
 // AutoModerationTriggerMetadata struct
 type AutoModerationTriggerMetadata struct {
-	KeywordFilter     []string `json:"keyword_filter,omitempty"`
-	RegexPatterns     []string `json:"regex_patterns,omitempty"`
-	Presets           []int    `json:"presets,omitempty"`
-	AllowList         []string `json:"allow_list,omitempty"`
-	MentionTotalLimit int      `json:"mention_total_limit,omitempty"`
+	KeywordFilter                []string `json:"keyword_filter,omitempty"`
+	RegexPatterns                []string `json:"regex_patterns,omitempty"`
+	Presets                      []int    `json:"presets,omitempty"`
+	AllowList                    []string `json:"allow_list,omitempty"`
+	MentionTotalLimit            int      `json:"mention_total_limit,omitempty"`
+	MentionRaidProtectionEnabled bool     `json:"mention_raid_protection_enabled,omitempty"`
 }
 
 // AutoModerationAction struct
@@ -450,6 +516,36 @@ type AutoModerationActionMetadata struct {
 	CustomMessage   string `json:"custom_message,omitempty"`
 }
 
+// AutoModerationTriggerType represents what kind of content triggers a rule
+const (
+	AutoModerationTriggerTypeKeyword       = 1
+	AutoModerationTriggerTypeSpam          = 3
+	AutoModerationTriggerTypeKeywordPreset = 4
+	AutoModerationTriggerTypeMentionSpam   = 5
+	AutoModerationTriggerTypeMemberProfile = 6
+)
+
+// AutoModerationEventType represents when a rule is checked
+const (
+	AutoModerationEventTypeMessageSend  = 1
+	AutoModerationEventTypeMemberUpdate = 2
+)
+
+// AutoModerationKeywordPresetType represents a Discord-maintained word list
+const (
+	AutoModerationKeywordPresetTypeProfanity     = 1
+	AutoModerationKeywordPresetTypeSexualContent = 2
+	AutoModerationKeywordPresetTypeSlurs         = 3
+)
+
+// AutoModerationActionType represents the action taken when a rule is triggered
+const (
+	AutoModerationActionTypeBlockMessage           = 1
+	AutoModerationActionTypeSendAlertMessage       = 2
+	AutoModerationActionTypeTimeout                = 3
+	AutoModerationActionTypeBlockMemberInteraction = 4
+)
+
 // Integration struct
 type Integration struct {
 	ID                string                 `json:"id"`
@@ -528,10 +624,31 @@ const (
 
 // ComponentType represents the type of component
 const (
-	ComponentTypeActionRow  = 1
-	ComponentTypeButton     = 2
-	ComponentTypeSelectMenu = 3
-	ComponentTypeTextInput  = 4
+	ComponentTypeActionRow         = 1
+	ComponentTypeButton            = 2
+	ComponentTypeSelectMenu        = 3 // string select
+	ComponentTypeTextInput         = 4
+	ComponentTypeUserSelect        = 5
+	ComponentTypeRoleSelect        = 6
+	ComponentTypeMentionableSelect = 7
+	ComponentTypeChannelSelect     = 8
+	ComponentTypeSection           = 9
+	ComponentTypeTextDisplay       = 10
+	ComponentTypeThumbnail         = 11
+	ComponentTypeMediaGallery      = 12
+	ComponentTypeFile              = 13
+	ComponentTypeSeparator         = 14
+	ComponentTypeContainer         = 17
+	ComponentTypeLabel             = 18
+
+	ComponentTypeStringSelect = ComponentTypeSelectMenu
+)
+
+// SelectDefaultValueType represents the type of a select menu default value
+const (
+	SelectDefaultValueTypeUser    = "user"
+	SelectDefaultValueTypeRole    = "role"
+	SelectDefaultValueTypeChannel = "channel"
 )
 
 // ButtonStyle represents the style of a button
@@ -543,6 +660,12 @@ const (
 	ButtonStyleLink      = 5
 )
 
+// SeparatorSpacing represents the padding of a separator component
+const (
+	SeparatorSpacingSmall = 1
+	SeparatorSpacingLarge = 2
+)
+
 // TextInputStyle represents the style of a text input
 const (
 	TextInputStyleShort     = 1
@@ -577,7 +700,7 @@ type InteractionData struct {
 	ComponentType int                                   `json:"component_type,omitempty"`
 	Values        []string                              `json:"values,omitempty"`
 	TargetID      string                                `json:"target_id,omitempty"`
-	Components    []MessageComponent                    `json:"components,omitempty"`
+	Components    MessageComponents                     `json:"components,omitempty"`
 }
 
 // ResolvedData contains resolved data for command options
@@ -607,24 +730,28 @@ type InteractionResponse struct {
 
 // InteractionCallbackData represents the data in an interaction response
 type InteractionCallbackData struct {
-	TTS             bool               `json:"tts,omitempty"`
-	Content         string             `json:"content,omitempty"`
-	Embeds          []*Embed           `json:"embeds,omitempty"`
-	AllowedMentions *AllowedMentions   `json:"allowed_mentions,omitempty"`
-	Flags           int                `json:"flags,omitempty"`
-	Components      []MessageComponent `json:"components,omitempty"`
-	Attachments     []*Attachment      `json:"attachments,omitempty"`
-	CustomID        string             `json:"custom_id,omitempty"`
-	Title           string             `json:"title,omitempty"`
+	TTS             bool                             `json:"tts,omitempty"`
+	Content         string                           `json:"content,omitempty"`
+	Embeds          []*Embed                         `json:"embeds,omitempty"`
+	AllowedMentions *AllowedMentions                 `json:"allowed_mentions,omitempty"`
+	Flags           int                              `json:"flags,omitempty"`
+	Components      MessageComponents                `json:"components,omitempty"`
+	Attachments     []*Attachment                    `json:"attachments,omitempty"`
+	CustomID        string                           `json:"custom_id,omitempty"`
+	Title           string                           `json:"title,omitempty"`
+	Choices         []ApplicationCommandOptionChoice `json:"choices,omitempty"`
 }
 
-// MessageComponent represents a message component
-type MessageComponent interface{}
+// MessageComponent represents a message component. It's implemented by the
+// component structs below; see MessageComponents for decoding.
+type MessageComponent interface {
+	ComponentType() int
+}
 
 // ActionRowComponent represents an action row component
 type ActionRowComponent struct {
-	Type       int                `json:"type"`
-	Components []MessageComponent `json:"components"`
+	Type       int               `json:"type"`
+	Components MessageComponents `json:"components"`
 }
 
 // ButtonComponent represents a button component
@@ -638,15 +765,26 @@ type ButtonComponent struct {
 	Disabled bool   `json:"disabled,omitempty"`
 }
 
-// SelectMenuComponent represents a select menu component
+// SelectMenuComponent represents any of the select menu components. Options
+// are only used by string selects, ChannelTypes only by channel selects and
+// DefaultValues only by the user, role, mentionable and channel selects.
 type SelectMenuComponent struct {
-	Type        int            `json:"type"`
-	CustomID    string         `json:"custom_id"`
-	Options     []SelectOption `json:"options,omitempty"`
-	Placeholder string         `json:"placeholder,omitempty"`
-	MinValues   *int           `json:"min_values,omitempty"`
-	MaxValues   int            `json:"max_values,omitempty"`
-	Disabled    bool           `json:"disabled,omitempty"`
+	Type          int                  `json:"type"`
+	CustomID      string               `json:"custom_id"`
+	Options       []SelectOption       `json:"options,omitempty"`
+	ChannelTypes  []int                `json:"channel_types,omitempty"`
+	Placeholder   string               `json:"placeholder,omitempty"`
+	DefaultValues []SelectDefaultValue `json:"default_values,omitempty"`
+	MinValues     *int                 `json:"min_values,omitempty"`
+	MaxValues     int                  `json:"max_values,omitempty"`
+	Disabled      bool                 `json:"disabled,omitempty"`
+	Values        []string             `json:"values,omitempty"` // the selected values, in modal submissions
+}
+
+// SelectDefaultValue represents a preselected entity in an auto-populated select menu
+type SelectDefaultValue struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
 }
 
 // SelectOption represents an option in a select menu
@@ -663,7 +801,7 @@ type TextInputComponent struct {
 	Type        int    `json:"type"`
 	CustomID    string `json:"custom_id"`
 	Style       int    `json:"style"`
-	Label       string `json:"label"`
+	Label       string `json:"label,omitempty"` // not allowed inside a LabelComponent
 	MinLength   int    `json:"min_length,omitempty"`
 	MaxLength   int    `json:"max_length,omitempty"`
 	Required    bool   `json:"required,omitempty"`
@@ -698,6 +836,7 @@ const (
 	MessageFlagUrgent               = 1 << 4
 	MessageFlagEphemeral            = 1 << 6
 	MessageFlagLoading              = 1 << 7
+	MessageFlagIsComponentsV2       = 1 << 15
 )
 
 // ApplicationCommandType represents the type of application command
@@ -721,3 +860,20 @@ const (
 	ApplicationCommandOptionTypeNumber          = 10
 	ApplicationCommandOptionTypeAttachment      = 11
 )
+
+// ChannelType represents the type of a channel
+const (
+	ChannelTypeGuildText          = 0
+	ChannelTypeDM                 = 1
+	ChannelTypeGuildVoice         = 2
+	ChannelTypeGroupDM            = 3
+	ChannelTypeGuildCategory      = 4
+	ChannelTypeGuildAnnouncement  = 5
+	ChannelTypeAnnouncementThread = 10
+	ChannelTypePublicThread       = 11
+	ChannelTypePrivateThread      = 12
+	ChannelTypeGuildStageVoice    = 13
+	ChannelTypeGuildDirectory     = 14
+	ChannelTypeGuildForum         = 15
+	ChannelTypeGuildMedia         = 16
+)
@@ -0,0 +1,380 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// WebhookClient talks to a single webhook using its token, so it doesn't need
+// a bot token and can be used on its own (CI notifiers, cron jobs, ...).
+type WebhookClient struct {
+	ID         string
+	Token      string
+	HTTPClient *http.Client
+	RateLimits map[string]time.Time
+	Mutex      sync.Mutex
+}
+
+func NewWebhookClient(id, token string) *WebhookClient {
+	return &WebhookClient{
+		ID:         id,
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		RateLimits: make(map[string]time.Time),
+	}
+}
+
+// NewWebhookClientFromURL parses a URL like https://discord.com/api/webhooks/{id}/{token}
+func NewWebhookClientFromURL(webhookURL string) (*WebhookClient, error) {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	for i, part := range parts {
+		if part == "webhooks" && i+2 < len(parts) {
+			return NewWebhookClient(parts[i+1], parts[i+2]), nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid webhook url: %s", webhookURL)
+}
+
+func (w *WebhookClient) endpoint() string {
+	return fmt.Sprintf("/webhooks/%s/%s", w.ID, w.Token)
+}
+
+// Execute sends a message through the webhook. wait=true is always set so
+// discord returns the created message. An optional thread ID posts into a thread.
+func (w *WebhookClient) Execute(message types.WebhookMessage, threadID ...string) (*types.Message, error) {
+	query := url.Values{}
+	query.Set("wait", "true")
+	if len(threadID) > 0 && threadID[0] != "" {
+		query.Set("thread_id", threadID[0])
+	}
+
+	resp, err := w.sendMessageRequest("POST", w.endpoint(), query, message)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, webhookError("execute webhook", resp)
+	}
+
+	var created types.Message
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (w *WebhookClient) GetMessage(messageID string, threadID ...string) (*types.Message, error) {
+	resp, err := w.sendRequest("GET", w.messageEndpoint(messageID), threadQuery(threadID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, webhookError("get webhook message", resp)
+	}
+
+	var message types.Message
+	if err := json.NewDecoder(resp.Body).Decode(&message); err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+func (w *WebhookClient) EditMessage(messageID string, message types.WebhookMessage, threadID ...string) (*types.Message, error) {
+	resp, err := w.sendMessageRequest("PATCH", w.messageEndpoint(messageID), threadQuery(threadID), message)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, webhookError("edit webhook message", resp)
+	}
+
+	var edited types.Message
+	if err := json.NewDecoder(resp.Body).Decode(&edited); err != nil {
+		return nil, err
+	}
+
+	return &edited, nil
+}
+
+func (w *WebhookClient) DeleteMessage(messageID string, threadID ...string) error {
+	resp, err := w.sendRequest("DELETE", w.messageEndpoint(messageID), threadQuery(threadID), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return webhookError("delete webhook message", resp)
+	}
+
+	return nil
+}
+
+func (w *WebhookClient) Get() (*types.Webhook, error) {
+	resp, err := w.sendRequest("GET", w.endpoint(), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, webhookError("get webhook", resp)
+	}
+
+	var webhook types.Webhook
+	if err := json.NewDecoder(resp.Body).Decode(&webhook); err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// Modify changes the webhook name and/or avatar (a data URI). Empty values are left unchanged.
+// Moving the webhook to another channel needs a bot token and isn't possible here.
+func (w *WebhookClient) Modify(name, avatar string) (*types.Webhook, error) {
+	webhookData := struct {
+		Name   string `json:"name,omitempty"`
+		Avatar string `json:"avatar,omitempty"`
+	}{
+		Name:   name,
+		Avatar: avatar,
+	}
+
+	resp, err := w.sendRequest("PATCH", w.endpoint(), nil, webhookData)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, webhookError("modify webhook", resp)
+	}
+
+	var webhook types.Webhook
+	if err := json.NewDecoder(resp.Body).Decode(&webhook); err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (w *WebhookClient) Delete() error {
+	resp, err := w.sendRequest("DELETE", w.endpoint(), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return webhookError("delete webhook", resp)
+	}
+
+	return nil
+}
+
+func (w *WebhookClient) messageEndpoint(messageID string) string {
+	return fmt.Sprintf("%s/messages/%s", w.endpoint(), messageID)
+}
+
+func threadQuery(threadID []string) url.Values {
+	if len(threadID) == 0 || threadID[0] == "" {
+		return nil
+	}
+	return url.Values{"thread_id": {threadID[0]}}
+}
+
+func webhookError(action string, resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	if len(body) > 0 {
+		return fmt.Errorf("failed to %s, status code: %d: %s", action, resp.StatusCode, body)
+	}
+	return fmt.Errorf("failed to %s, status code: %d", action, resp.StatusCode)
+}
+
+// sendMessageRequest sends a webhook message as JSON, or as multipart/form-data
+// when it carries files.
+func (w *WebhookClient) sendMessageRequest(method, endpoint string, query url.Values, message types.WebhookMessage) (*http.Response, error) {
+	if len(message.Files) == 0 {
+		return w.sendRequest(method, endpoint, query, message)
+	}
+
+	body, contentType, err := encodeMultipartMessage(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.do(method, endpoint, query, body, contentType)
+}
+
+func (w *WebhookClient) sendRequest(method, endpoint string, query url.Values, body interface{}) (*http.Response, error) {
+	var requestBody []byte
+	if body != nil {
+		var err error
+		requestBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return w.do(method, endpoint, query, requestBody, "application/json")
+}
+
+func (w *WebhookClient) do(method, endpoint string, query url.Values, body []byte, contentType string) (*http.Response, error) {
+	w.Mutex.Lock()
+	if reset, exists := w.RateLimits[endpoint]; exists && time.Now().Before(reset) {
+		delay := time.Until(reset)
+		w.Mutex.Unlock()
+		time.Sleep(delay)
+	} else {
+		w.Mutex.Unlock()
+	}
+
+	requestURL := DiscordAPIURL + endpoint
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("User-Agent", "DiscordBot (https://github.com/nyrilol/discord-go, 1.0)")
+
+	resp, err := w.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		var rateLimit struct {
+			RetryAfter float64 `json:"retry_after"`
+		}
+		json.NewDecoder(resp.Body).Decode(&rateLimit)
+		w.Mutex.Lock()
+		w.RateLimits[endpoint] = time.Now().Add(time.Duration(rateLimit.RetryAfter * float64(time.Second)))
+		w.Mutex.Unlock()
+		resp.Body.Close()
+		return w.do(method, endpoint, query, body, contentType)
+	}
+
+	return resp, nil
+}
+
+// encodeMultipartMessage builds a payload_json + files[n] body. The files are
+// read fully, so a request retried after a rate limit sends the same bytes.
+func encodeMultipartMessage(message types.WebhookMessage) ([]byte, string, error) {
+	payload := []byte(message.PayloadJSON)
+	if len(payload) == 0 {
+		var err error
+		payload, err = json.Marshal(withFileAttachments(message))
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="payload_json"`)
+	header.Set("Content-Type", "application/json")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(payload); err != nil {
+		return nil, "", err
+	}
+
+	for i, file := range message.Files {
+		if file == nil || file.Reader == nil {
+			return nil, "", errors.New("webhook file has no reader")
+		}
+
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="files[%d]"; filename="%s"`, i, escapeQuotes(file.Name)))
+		header.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := io.Copy(part, file.Reader); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// withFileAttachments adds an attachment entry per file unless the caller set them already
+func withFileAttachments(message types.WebhookMessage) interface{} {
+	if len(message.Attachments) > 0 {
+		return message
+	}
+
+	type attachment struct {
+		ID       int    `json:"id"`
+		Filename string `json:"filename"`
+	}
+
+	attachments := make([]attachment, 0, len(message.Files))
+	for i, file := range message.Files {
+		if file == nil {
+			continue
+		}
+		attachments = append(attachments, attachment{ID: i, Filename: file.Name})
+	}
+
+	return struct {
+		types.WebhookMessage
+		Attachments []attachment `json:"attachments"`
+	}{
+		WebhookMessage: message,
+		Attachments:    attachments,
+	}
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
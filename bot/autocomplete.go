@@ -0,0 +1,138 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+const (
+	// AutocompleteTimeout is how long discord waits for autocomplete choices
+	AutocompleteTimeout    = 3 * time.Second
+	MaxAutocompleteChoices = 25
+)
+
+var (
+	ErrAlreadyResponded    = errors.New("interaction already responded to")
+	ErrAutocompleteExpired = errors.New("autocomplete response is past discord's 3 second limit")
+)
+
+type autocompleteKey struct {
+	command string
+	option  string
+}
+
+// Autocomplete registers a handler for an autocomplete option. command is the
+// full command path, so options of subcommands use e.g. "config set".
+func (ih *InteractionHandler) Autocomplete(command, option string, handler AutocompleteHandler) {
+	ih.commandMutex.Lock()
+	defer ih.commandMutex.Unlock()
+	ih.autocompletes[autocompleteKey{command: normalizeCommandPath(command), option: option}] = handler
+}
+
+func (ih *InteractionHandler) handleAutocomplete(interaction *types.Interaction) {
+	receivedAt := time.Now()
+	path, options := resolveCommandPath(interaction.Data)
+
+	ctx := &AutocompleteContext{
+		Interaction: interaction,
+		Bot:         ih.bot,
+		Command:     path,
+		Options:     make(map[string]interface{}),
+		receivedAt:  receivedAt,
+	}
+
+	found := false
+	for _, option := range options {
+		if option.Focused {
+			ctx.Focused = option
+			found = true
+			continue
+		}
+		ctx.Options[option.Name] = option.Value
+	}
+
+	if !found {
+		ih.bot.logger.Warnf("Autocomplete for %s has no focused option", path)
+		return
+	}
+
+	ih.commandMutex.Lock()
+	handler, exists := ih.autocompletes[autocompleteKey{command: path, option: ctx.Focused.Name}]
+	ih.commandMutex.Unlock()
+
+	if !exists {
+		ih.bot.logger.Warnf("No autocomplete handler found for %s %s", path, ctx.Focused.Name)
+		return
+	}
+
+	handler(ctx)
+}
+
+// Value is what the user has typed into the focused option so far
+func (ctx *AutocompleteContext) Value() string {
+	if ctx.Focused.Value == nil {
+		return ""
+	}
+	return fmt.Sprint(ctx.Focused.Value)
+}
+
+// Respond sends the choices shown to the user. Only the first 25 are sent, and
+// nothing is sent once the 3 second window has passed.
+func (ctx *AutocompleteContext) Respond(choices ...types.ApplicationCommandOptionChoice) error {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	if ctx.responded {
+		return ErrAlreadyResponded
+	}
+
+	if elapsed := time.Since(ctx.receivedAt); elapsed > AutocompleteTimeout {
+		return fmt.Errorf("%w (took %v)", ErrAutocompleteExpired, elapsed.Round(time.Millisecond))
+	}
+
+	if len(choices) > MaxAutocompleteChoices {
+		choices = choices[:MaxAutocompleteChoices]
+	}
+
+	if err := ctx.Bot.sendAutocompleteResult(ctx.Interaction.ID, ctx.Interaction.Token, choices); err != nil {
+		return err
+	}
+
+	ctx.responded = true
+	return nil
+}
+
+// RespondStrings is Respond for string options where name and value are the same
+func (ctx *AutocompleteContext) RespondStrings(values ...string) error {
+	choices := make([]types.ApplicationCommandOptionChoice, len(values))
+	for i, value := range values {
+		choices[i] = types.ApplicationCommandOptionChoice{Name: value, Value: value}
+	}
+	return ctx.Respond(choices...)
+}
+
+// resolveCommandPath walks subcommand groups and subcommands, returning the full
+// command path and the options of the leaf subcommand.
+func resolveCommandPath(data types.InteractionData) (string, []types.ApplicationCommandInteractionOption) {
+	path := []string{data.Name}
+	options := data.Options
+
+	for len(options) == 1 {
+		option := options[0]
+		if option.Type != types.ApplicationCommandOptionTypeSubCommandGroup && option.Type != types.ApplicationCommandOptionTypeSubCommand {
+			break
+		}
+		path = append(path, option.Name)
+		options = option.Options
+	}
+
+	return strings.Join(path, " "), options
+}
+
+func normalizeCommandPath(path string) string {
+	return strings.Join(strings.Fields(path), " ")
+}
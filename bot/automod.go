@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"github.com/nyrilol/discord-go/api/types"
+	"github.com/nyrilol/discord-go/gateway"
+)
+
+// AutoModerationFilter narrows which action executions reach a handler.
+// Empty slices match everything.
+type AutoModerationFilter struct {
+	RuleIDs     []string
+	ActionTypes []int
+}
+
+type AutoModerationHandler func(event types.AutoModerationActionExecutionEvent)
+
+func (f AutoModerationFilter) matches(event types.AutoModerationActionExecutionEvent) bool {
+	if len(f.RuleIDs) > 0 && !containsString(f.RuleIDs, event.RuleID) {
+		return false
+	}
+
+	if len(f.ActionTypes) > 0 {
+		matched := false
+		for _, actionType := range f.ActionTypes {
+			if actionType == event.Action.Type {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// OnAutoModerationAction subscribes to AUTO_MODERATION_ACTION_EXECUTION events.
+// Needs the IntentAutoModerationExecution intent.
+func (b *Bot) OnAutoModerationAction(filter AutoModerationFilter, handler AutoModerationHandler) *gateway.Subscription {
+	return b.On("AUTO_MODERATION_ACTION_EXECUTION", func(event types.AutoModerationActionExecutionEvent) {
+		if !filter.matches(event) {
+			return
+		}
+		handler(event)
+	}, types.AutoModerationActionExecutionEvent{})
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
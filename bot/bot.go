@@ -7,49 +7,97 @@ import (
 	"github.com/nyrilol/discord-go/utils"
 	"strings"
 	"sync"
+	"time"
 )
 
 type Bot struct {
 	token    string
 	gateway  *gateway.Gateway
 	logger   utils.Logger
-	handlers sync.Map
 	commands map[string]CommandHandler
 	mu       sync.RWMutex
+	pending  sync.Map // interaction ID -> *pendingResponse, for HTTP interactions
+
+	selfID             string // set on READY
+	commandMiddlewares []CommandMiddleware
 }
 
 type InteractionHandler struct {
-	bot            *Bot
-	commands       map[string]CommandHandler
-	buttons        map[string]ButtonHandler
-	selectMenus    map[string]SelectMenuHandler
-	modals         map[string]ModalHandler
-	componentMutex sync.Mutex
-	commandMutex   sync.Mutex
-	globalCommands map[string]types.ApplicationCommand
-	guildCommands  map[types.Snowflake]map[string]types.ApplicationCommand // guildID -> commandName -> command
+	bot                *Bot
+	commands           map[string]CommandHandler
+	userCommands       map[string]UserCommandHandler
+	msgCommands        map[string]MessageCommandHandler
+	buttons            map[string]ButtonHandler
+	selectMenus        map[string]SelectMenuHandler
+	modals             map[string]ModalHandler
+	autocompletes      map[autocompleteKey]AutocompleteHandler
+	patterns           []*customIDPattern // pattern custom ID routes, in registration order
+	paginators         map[string]*Paginator
+	paginatorOnce      sync.Once
+	routes             []*commandRoute // subcommand routes, in registration order
+	autoDefer          time.Duration
+	autoDeferEphemeral bool
+	componentMutex     sync.Mutex
+	commandMutex       sync.Mutex
+	globalCommands     map[commandKey]types.ApplicationCommand
+	guildCommands      map[types.Snowflake]map[commandKey]types.ApplicationCommand // guildID -> name and type -> command
+	syncedGuilds       map[types.Snowflake]bool                                    // guilds that had commands, so emptied ones get cleared
+	placeholders       map[commandKey]bool                                         // global definitions added by UserCommand and MessageCommand
 }
 
 type CommandHandler func(ctx *CommandContext)
 type ButtonHandler func(ctx *ComponentContext)
 type SelectMenuHandler func(ctx *ComponentContext)
 type ModalHandler func(ctx *ModalContext)
+type AutocompleteHandler func(ctx *AutocompleteContext)
+type UserCommandHandler func(ctx *UserCommandContext)
+type MessageCommandHandler func(ctx *MessageCommandContext)
 type CommandContext struct {
 	Interaction *types.Interaction
 	Bot         *Bot
-	Options     map[string]interface{}
+	Command     string                 // full command path, e.g. "config set prefix"
+	Options     map[string]interface{} // options of the invoked (sub)command only
+	Response    *InteractionResponder
+	options     map[string]types.ApplicationCommandInteractionOption
+	optionErr   error
+	values      map[string]interface{}
+}
+type UserCommandContext struct {
+	*CommandContext
+	Target *types.User
+	Member *types.GuildMember // nil outside guilds
+}
+type MessageCommandContext struct {
+	*CommandContext
+	Target *types.Message
 }
 type ComponentContext struct {
 	Interaction *types.Interaction
 	Bot         *Bot
 	CustomID    string
+	Params      map[string]string // parameters captured by a custom ID pattern
 	Values      []string
+	Response    *InteractionResponder
 }
 type ModalContext struct {
 	Interaction *types.Interaction
 	Bot         *Bot
 	CustomID    string
+	Params      map[string]string // parameters captured by a custom ID pattern
 	Inputs      map[string]string
+	Selects     map[string][]string // values of select menus in labels, by custom ID
+	Response    *InteractionResponder
+}
+
+type AutocompleteContext struct {
+	Interaction *types.Interaction
+	Bot         *Bot
+	Command     string // full command path, e.g. "config set"
+	Focused     types.ApplicationCommandInteractionOption
+	Options     map[string]interface{} // partial values of the other options
+	receivedAt  time.Time
+	responded   bool
+	mu          sync.Mutex
 }
 
 type MessageContext struct {
@@ -94,17 +142,62 @@ func isValidToken(token string) bool {
 }
 
 func (bot *Bot) SendInteractionResponse(interactionID types.Snowflake, token string, response types.InteractionResponse) error {
+	if bot.deliverPending(interactionID, response) {
+		return nil
+	}
 	return bot.gateway.SendInteractionResponse(interactionID, token, response)
 }
 
 func (bot *Bot) CreateGlobalApplicationCommand(command types.ApplicationCommand) error {
+	if err := api.ValidateApplicationCommand(command); err != nil {
+		return err
+	}
 	return bot.gateway.CreateGlobalApplicationCommand(command)
 }
 
+// GetApplicationCommands lists the registered commands. An empty guildID lists global commands.
+func (bot *Bot) GetApplicationCommands(guildID types.Snowflake, withLocalizations bool) ([]types.ApplicationCommand, error) {
+	return bot.gateway.GetApplicationCommands(guildID, withLocalizations)
+}
+
+func (bot *Bot) EditApplicationCommand(guildID, commandID types.Snowflake, command types.ApplicationCommand) (*types.ApplicationCommand, error) {
+	if err := api.ValidateApplicationCommand(command); err != nil {
+		return nil, err
+	}
+	return bot.gateway.EditApplicationCommand(guildID, commandID, command)
+}
+
+func (bot *Bot) DeleteApplicationCommand(guildID, commandID types.Snowflake) error {
+	return bot.gateway.DeleteApplicationCommand(guildID, commandID)
+}
+
+func (bot *Bot) BulkOverwriteApplicationCommands(guildID types.Snowflake, commands []types.ApplicationCommand) ([]types.ApplicationCommand, error) {
+	for _, command := range commands {
+		if err := api.ValidateApplicationCommand(command); err != nil {
+			return nil, err
+		}
+	}
+	return bot.gateway.BulkOverwriteApplicationCommands(guildID, commands)
+}
+
+func (bot *Bot) GetGuildApplicationCommandPermissions(guildID types.Snowflake) ([]types.GuildApplicationCommandPermissions, error) {
+	return bot.gateway.GetGuildApplicationCommandPermissions(guildID)
+}
+
+func (bot *Bot) GetApplicationCommandPermissions(guildID, commandID types.Snowflake) (*types.GuildApplicationCommandPermissions, error) {
+	return bot.gateway.GetApplicationCommandPermissions(guildID, commandID)
+}
+
+func (bot *Bot) EditApplicationCommandPermissions(guildID, commandID types.Snowflake, bearerToken string, permissions []types.ApplicationCommandPermissions) (*types.GuildApplicationCommandPermissions, error) {
+	return bot.gateway.EditApplicationCommandPermissions(guildID, commandID, bearerToken, permissions)
+}
+
 func (bot *Bot) registerDefaultHandlers() {
-	bot.On("READY", func(event types.ReadyEvent) {
+	bot.Once("READY", func(event types.ReadyEvent) {
+		bot.mu.Lock()
+		bot.selfID = event.User.ID
+		bot.mu.Unlock()
 		bot.logger.Infof("Bot is ready: %s (Shard %d)", event.User.Username, event.Shard)
-		bot.gateway.RemoveHandler("READY")
 	}, types.ReadyEvent{})
 }
 
@@ -132,23 +225,16 @@ func (b *Bot) handleInteraction(interaction types.Interaction) {
 		return
 	}
 
-	options := make(map[string]interface{})
-	for _, option := range interaction.Data.Options {
-		options[option.Name] = option.Value
-	}
+	ctx := newCommandContext(&interaction, b, interaction.Data.Name, interaction.Data.Options)
 
-	ctx := &CommandContext{
-		Interaction: &interaction,
-		Bot:         b,
-		Options:     options,
-	}
-
-	handler(ctx)
+	b.runCommand(ctx, func() { handler(ctx) })
 }
 
+// AddMessageHandler calls handler for messages that are prefix or start with
+// prefix and a space. NewTextCommandRouter parses commands and arguments.
 func (b *Bot) AddMessageHandler(prefix string, handler func(*MessageContext)) {
 	b.On("MESSAGE_CREATE", func(event types.Message) {
-		if event.Content == prefix {
+		if event.Content == prefix || strings.HasPrefix(event.Content, prefix+" ") {
 			handler(&MessageContext{
 				Message: &event,
 				Bot:     b,
@@ -200,24 +286,16 @@ func (b *Bot) NewSelectMenu(customID, placeholder string, options []types.Select
 
 // response helpers
 func (b *Bot) RespondToInteraction(ctx *CommandContext, content string) error {
-	response := types.InteractionResponse{
-		Type: types.InteractionResponseTypeChannelMessageWithSource,
-		Data: &types.InteractionCallbackData{
-			Content: content,
-		},
-	}
-	return b.SendInteractionResponse(ctx.Interaction.ID, ctx.Interaction.Token, response)
+	return ctx.Response.Respond(types.InteractionCallbackData{
+		Content: content,
+	})
 }
 
 func (b *Bot) RespondWithComponents(ctx *CommandContext, content string, components []types.MessageComponent) error {
-	response := types.InteractionResponse{
-		Type: types.InteractionResponseTypeChannelMessageWithSource,
-		Data: &types.InteractionCallbackData{
-			Content:    content,
-			Components: components,
-		},
-	}
-	return b.SendInteractionResponse(ctx.Interaction.ID, ctx.Interaction.Token, response)
+	return ctx.Response.Respond(types.InteractionCallbackData{
+		Content:    content,
+		Components: components,
+	})
 }
 
 // modal helpers
@@ -250,13 +328,27 @@ func (b *Bot) NewTextInputRow(customID, label string, style int, required bool,
 }
 
 func (b *Bot) RespondWithModal(ctx *ComponentContext, modal types.InteractionResponse) error {
-	return b.SendInteractionResponse(ctx.Interaction.ID, ctx.Interaction.Token, modal)
+	return ctx.Response.Callback(modal)
 }
 
 // cpompent handlers
+// customID can be a pattern like "delete:{id}" or "page:*", see InteractionHandler.Button
 func (b *Bot) AddComponentHandler(customID string, handler func(*ComponentContext)) {
+	pattern := &customIDPattern{pattern: customID, segments: []patternSegment{{literal: customID}}}
+	if isPattern(customID) {
+		segments, err := compilePattern(customID)
+		if err != nil {
+			b.logger.Errorf("%v", err)
+			return
+		}
+		pattern.segments = segments
+	}
+
 	b.On("INTERACTION_CREATE", func(interaction types.Interaction) {
-		if interaction.Type == types.InteractionTypeMessageComponent && interaction.Data.CustomID == customID {
+		if interaction.Type != types.InteractionTypeMessageComponent {
+			return
+		}
+		if params, ok := pattern.match(interaction.Data.CustomID); ok {
 			var values []string
 			if interaction.Data.Values != nil {
 				values = interaction.Data.Values
@@ -265,8 +357,10 @@ func (b *Bot) AddComponentHandler(customID string, handler func(*ComponentContex
 			handler(&ComponentContext{
 				Interaction: &interaction,
 				Bot:         b,
-				CustomID:    customID,
+				CustomID:    interaction.Data.CustomID,
+				Params:      params,
 				Values:      values,
+				Response:    newInteractionResponder(b, &interaction),
 			})
 		}
 	}, types.Interaction{})
@@ -276,22 +370,15 @@ func (b *Bot) AddComponentHandler(customID string, handler func(*ComponentContex
 func (b *Bot) SetModalHandler(handler func(*ModalContext)) {
 	b.On("INTERACTION_CREATE", func(interaction types.Interaction) {
 		if interaction.Type == types.InteractionTypeModalSubmit {
-			inputs := make(map[string]string)
-			for _, row := range interaction.Data.Components {
-				if actionRow, ok := row.(types.ActionRowComponent); ok {
-					for _, component := range actionRow.Components {
-						if textInput, ok := component.(types.TextInputComponent); ok {
-							inputs[textInput.CustomID] = textInput.Value
-						}
-					}
-				}
-			}
+			inputs, selects := modalInputs(interaction.Data.Components)
 
 			handler(&ModalContext{
 				Interaction: &interaction,
 				Bot:         b,
 				CustomID:    interaction.Data.CustomID,
 				Inputs:      inputs,
+				Selects:     selects,
+				Response:    newInteractionResponder(b, &interaction),
 			})
 		}
 	}, types.Interaction{})
@@ -303,14 +390,38 @@ func (bot *Bot) Start() {
 	select {}
 }
 
-func (bot *Bot) On(eventName string, handler interface{}, event_type interface{}) {
+// On registers a handler for an event. Call Remove on the returned
+// subscription to unregister just this handler.
+func (bot *Bot) On(eventName string, handler interface{}, event_type interface{}) *gateway.Subscription {
 	eventName = strings.ToUpper(eventName) // just incase retard user
-	bot.handlers.Store(eventName, handler)
-	bot.gateway.RegisterHandler(eventName, handler, event_type)
+	return bot.gateway.RegisterHandler(eventName, handler, event_type)
 }
 
+// Once registers a handler that runs for the next event only
+func (bot *Bot) Once(eventName string, handler interface{}, event_type interface{}) *gateway.Subscription {
+	eventName = strings.ToUpper(eventName)
+	return bot.gateway.RegisterOnceHandler(eventName, handler, event_type)
+}
+
+// RemoveHandler removes every handler of an event, including the ones
+// registered by the library. Prefer Subscription.Remove.
 func (bot *Bot) RemoveHandler(eventName string) {
 	eventName = strings.ToUpper(eventName) // just incase retard user
-	bot.handlers.Delete(eventName)
 	bot.gateway.RemoveHandler(eventName)
 }
+
+// SetDispatcher configures how gateway events reach the handlers: on the read
+// loop in order (the default), on a worker pool or a goroutine each, see
+// gateway.DispatcherConfig.
+func (bot *Bot) SetDispatcher(config gateway.DispatcherConfig) {
+	bot.gateway.SetDispatcher(config)
+}
+
+// Handlers lists the registered event handlers, optionally only for some events
+func (bot *Bot) Handlers(eventNames ...string) []gateway.HandlerInfo {
+	names := make([]string, len(eventNames))
+	for i, name := range eventNames {
+		names[i] = strings.ToUpper(name)
+	}
+	return bot.gateway.Handlers(names...)
+}
@@ -0,0 +1,241 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+var (
+	// ErrCollectorIdle ends a collector when nothing arrived within its IdleTimeout
+	ErrCollectorIdle = errors.New("collector timed out waiting for events")
+	// ErrCollectorStopped ends a collector that was stopped by hand
+	ErrCollectorStopped = errors.New("collector was stopped")
+)
+
+// addTemporaryHandler registers a handler that can be removed on its own,
+// without dropping the other handlers of the event. The event is decoded into
+// the type of the handler's parameter.
+func (bot *Bot) addTemporaryHandler(eventName string, handler interface{}) func() {
+	subscription := bot.gateway.RegisterHandler(strings.ToUpper(eventName), handler)
+	return func() { subscription.Remove() }
+}
+
+// WaitFor blocks until an event passes filter and returns it. A nil filter
+// accepts the first event. The handler is removed again when WaitFor returns.
+//
+//	msg, err := bot.WaitFor(ctx, b, "MESSAGE_CREATE", func(m types.Message) bool {
+//		return m.ChannelID == channelID && m.Author.ID == userID
+//	})
+func WaitFor[T any](ctx context.Context, bot *Bot, eventName string, filter func(T) bool) (T, error) {
+	result := make(chan T, 1)
+
+	remove := bot.addTemporaryHandler(eventName, func(event T) {
+		if filter != nil && !filter(event) {
+			return
+		}
+		select {
+		case result <- event:
+		default:
+		}
+	})
+	defer remove()
+
+	select {
+	case event := <-result:
+		return event, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// CollectorOptions limits how long a collector runs
+type CollectorOptions struct {
+	Max         int           // stop after this many items, zero for no limit
+	IdleTimeout time.Duration // stop when nothing was collected for this long
+}
+
+// Collector gathers events until its context ends, Max items were collected,
+// the idle timeout passes or Stop is called. Items are sent on C as they arrive
+// (C is closed at the end) and are also kept for Collected and Wait.
+type Collector[T any] struct {
+	C <-chan T
+
+	items   chan T
+	done    chan struct{}
+	remove  func()
+	opts    CollectorOptions
+	mu      sync.Mutex
+	results []T
+	err     error
+	idle    *time.Timer
+	ended   bool
+}
+
+// collectorBuffer is the size of C when no Max is set
+const collectorBuffer = 100
+
+// Collect starts a collector for an event. filter may be nil to collect every event.
+func Collect[T any](ctx context.Context, bot *Bot, eventName string, filter func(T) bool, opts CollectorOptions) *Collector[T] {
+	return newCollector(ctx, bot, eventName, opts, func(event T) (T, bool) {
+		return event, filter == nil || filter(event)
+	})
+}
+
+// newCollector registers a handler for events of type E, turning the accepted
+// ones into items of type T.
+func newCollector[E, T any](ctx context.Context, bot *Bot, eventName string, opts CollectorOptions, accept func(E) (T, bool)) *Collector[T] {
+	size := collectorBuffer
+	if opts.Max > 0 {
+		size = opts.Max
+	}
+
+	c := &Collector[T]{
+		items: make(chan T, size),
+		done:  make(chan struct{}),
+		opts:  opts,
+	}
+	c.C = c.items
+
+	c.remove = bot.addTemporaryHandler(eventName, func(event E) {
+		item, ok := accept(event)
+		if !ok {
+			return
+		}
+		if !c.add(item) {
+			bot.logger.Warnf("Collector for %s is full, dropping event", eventName)
+		}
+	})
+
+	if opts.IdleTimeout > 0 {
+		c.idle = time.AfterFunc(opts.IdleTimeout, func() { c.end(ErrCollectorIdle) })
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.end(ctx.Err())
+		case <-c.done:
+		}
+	}()
+
+	return c
+}
+
+// add records an item. It returns false when the item didn't fit in C.
+func (c *Collector[T]) add(item T) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ended {
+		return true
+	}
+
+	c.results = append(c.results, item)
+	sent := true
+	select {
+	case c.items <- item:
+	default:
+		sent = false
+	}
+
+	if c.idle != nil {
+		c.idle.Reset(c.opts.IdleTimeout)
+	}
+	if c.opts.Max > 0 && len(c.results) >= c.opts.Max {
+		c.endLocked(nil)
+	}
+	return sent
+}
+
+func (c *Collector[T]) end(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.endLocked(err)
+}
+
+func (c *Collector[T]) endLocked(err error) {
+	if c.ended {
+		return
+	}
+	c.ended = true
+	c.err = err
+	c.remove()
+	if c.idle != nil {
+		c.idle.Stop()
+	}
+	close(c.items)
+	close(c.done)
+}
+
+// Stop ends the collector early
+func (c *Collector[T]) Stop() {
+	c.end(ErrCollectorStopped)
+}
+
+// Done is closed when the collector ends
+func (c *Collector[T]) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err tells why the collector ended: nil when Max was reached, ErrCollectorIdle,
+// ErrCollectorStopped or the context's error.
+func (c *Collector[T]) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Collected returns the items gathered so far
+func (c *Collector[T]) Collected() []T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]T(nil), c.results...)
+}
+
+// Wait blocks until the collector ends and returns everything it collected
+func (c *Collector[T]) Wait() ([]T, error) {
+	<-c.done
+	return c.Collected(), c.Err()
+}
+
+// CollectMessages collects messages sent in a channel, only by userID unless it's empty
+func CollectMessages(ctx context.Context, bot *Bot, channelID, userID string, opts CollectorOptions) *Collector[types.Message] {
+	return Collect(ctx, bot, "MESSAGE_CREATE", func(message types.Message) bool {
+		return message.ChannelID == channelID && (userID == "" || message.Author.ID == userID)
+	}, opts)
+}
+
+// CollectReactions collects reactions added to a message, only by userID unless it's empty
+func CollectReactions(ctx context.Context, bot *Bot, messageID, userID string, opts CollectorOptions) *Collector[types.MessageReactionAddEvent] {
+	return Collect(ctx, bot, "MESSAGE_REACTION_ADD", func(event types.MessageReactionAddEvent) bool {
+		return event.MessageID == messageID && (userID == "" || event.UserID == userID)
+	}, opts)
+}
+
+// CollectComponents collects button and select clicks on a message, only by
+// userID unless it's empty. Each click has to be answered through its context.
+func CollectComponents(ctx context.Context, bot *Bot, messageID, userID string, opts CollectorOptions) *Collector[*ComponentContext] {
+	return newCollector(ctx, bot, "INTERACTION_CREATE", opts, func(interaction types.Interaction) (*ComponentContext, bool) {
+		if interaction.Type != types.InteractionTypeMessageComponent || interaction.Message == nil || interaction.Message.ID != messageID {
+			return nil, false
+		}
+		if userID != "" && interactionUserID(&interaction) != userID {
+			return nil, false
+		}
+
+		return &ComponentContext{
+			Interaction: &interaction,
+			Bot:         bot,
+			CustomID:    interaction.Data.CustomID,
+			Params:      map[string]string{},
+			Values:      interaction.Data.Values,
+			Response:    newInteractionResponder(bot, &interaction),
+		}, true
+	})
+}
@@ -0,0 +1,127 @@
+package bot
+
+import (
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// Respond sends a new message in reply to the component, or a followup when
+// the interaction was already acknowledged.
+func (ctx *ComponentContext) Respond(content string, ephemeral ...bool) error {
+	return ctx.Response.Respond(types.InteractionCallbackData{
+		Content: content,
+		Flags:   ephemeralFlags(ephemeral),
+	})
+}
+
+// Update edits the message the component belongs to. Components are left as
+// they are unless new ones are given.
+func (ctx *ComponentContext) Update(content string, components ...types.MessageComponent) error {
+	return ctx.Response.Update(types.InteractionCallbackData{
+		Content:    content,
+		Components: components,
+	})
+}
+
+func (ctx *ComponentContext) DeferUpdate() error {
+	return ctx.Response.DeferUpdate()
+}
+
+func (ctx *ComponentContext) Followup(content string, ephemeral ...bool) error {
+	_, err := ctx.Response.Followup(types.WebhookMessage{
+		Content: content,
+		Flags:   ephemeralFlags(ephemeral),
+	})
+	return err
+}
+
+// ShowModal opens a modal; it has to be the initial response
+func (ctx *ComponentContext) ShowModal(modal *Modal) error {
+	response, err := modal.response()
+	if err != nil {
+		return err
+	}
+	return ctx.Response.Callback(response)
+}
+
+func (ctx *ModalContext) Respond(content string, ephemeral ...bool) error {
+	return ctx.Response.Respond(types.InteractionCallbackData{
+		Content: content,
+		Flags:   ephemeralFlags(ephemeral),
+	})
+}
+
+// Update edits the message of the component that opened the modal. Only
+// works for modals shown from a component.
+func (ctx *ModalContext) Update(content string, components ...types.MessageComponent) error {
+	return ctx.Response.Update(types.InteractionCallbackData{
+		Content:    content,
+		Components: components,
+	})
+}
+
+func (ctx *ModalContext) DeferUpdate() error {
+	return ctx.Response.DeferUpdate()
+}
+
+func (ctx *ModalContext) Followup(content string, ephemeral ...bool) error {
+	_, err := ctx.Response.Followup(types.WebhookMessage{
+		Content: content,
+		Flags:   ephemeralFlags(ephemeral),
+	})
+	return err
+}
+
+func (ctx *ComponentContext) resolved() *types.ResolvedData {
+	if ctx.Interaction == nil || ctx.Interaction.Data.Resolved == nil {
+		return &types.ResolvedData{}
+	}
+	return ctx.Interaction.Data.Resolved
+}
+
+// SelectedUsers returns the users picked in a user or mentionable select, in selection order
+func (ctx *ComponentContext) SelectedUsers() []types.User {
+	resolved := ctx.resolved()
+	var users []types.User
+	for _, id := range ctx.Values {
+		if user, exists := resolved.Users[id]; exists {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+// SelectedMembers returns the members of the picked users. Only set in guilds.
+func (ctx *ComponentContext) SelectedMembers() []types.GuildMember {
+	resolved := ctx.resolved()
+	var members []types.GuildMember
+	for _, id := range ctx.Values {
+		if member, ok := resolveMember(resolved, id); ok {
+			members = append(members, *member)
+		}
+	}
+	return members
+}
+
+// SelectedRoles returns the roles picked in a role or mentionable select
+func (ctx *ComponentContext) SelectedRoles() []types.Role {
+	resolved := ctx.resolved()
+	var roles []types.Role
+	for _, id := range ctx.Values {
+		if role, exists := resolved.Roles[id]; exists {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// SelectedChannels returns the (partial) channels picked in a channel select
+func (ctx *ComponentContext) SelectedChannels() []types.Channel {
+	resolved := ctx.resolved()
+	var channels []types.Channel
+	for _, id := range ctx.Values {
+		if channel, exists := resolved.Channels[id]; exists {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
@@ -0,0 +1,99 @@
+package bot
+
+import (
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// UserCommand registers a handler for a user context-menu command and adds its
+// definition to the global commands, so the next SyncCommands creates it.
+func (ih *InteractionHandler) UserCommand(name string, handler UserCommandHandler) {
+	ih.commandMutex.Lock()
+	defer ih.commandMutex.Unlock()
+	ih.userCommands[name] = handler
+	ih.defineContextMenuCommand(name, types.ApplicationCommandTypeUser)
+}
+
+// MessageCommand registers a handler for a message context-menu command and adds
+// its definition to the global commands, so the next SyncCommands creates it.
+func (ih *InteractionHandler) MessageCommand(name string, handler MessageCommandHandler) {
+	ih.commandMutex.Lock()
+	defer ih.commandMutex.Unlock()
+	ih.msgCommands[name] = handler
+	ih.defineContextMenuCommand(name, types.ApplicationCommandTypeMessage)
+}
+
+// defineContextMenuCommand adds a global definition unless the command was
+// already registered (possibly for a guild). The definition is a placeholder:
+// RegisterCommand replaces it, globally or for a guild. Callers must hold
+// commandMutex.
+func (ih *InteractionHandler) defineContextMenuCommand(name string, commandType int) {
+	key := commandKey{name: name, commandType: commandType}
+	if _, exists := ih.globalCommands[key]; exists {
+		return
+	}
+	for _, commands := range ih.guildCommands {
+		if _, exists := commands[key]; exists {
+			return
+		}
+	}
+
+	ih.globalCommands[key] = types.ApplicationCommand{
+		Type: commandType,
+		Name: name,
+	}
+	ih.placeholders[key] = true
+}
+
+func (ih *InteractionHandler) handleUserCommand(interaction *types.Interaction) {
+	ih.commandMutex.Lock()
+	handler, exists := ih.userCommands[interaction.Data.Name]
+	ih.commandMutex.Unlock()
+
+	if !exists {
+		ih.bot.logger.Warnf("No handler found for user command: %s", interaction.Data.Name)
+		return
+	}
+
+	ctx := &UserCommandContext{
+		CommandContext: newCommandContext(interaction, ih.bot, interaction.Data.Name, nil),
+	}
+
+	resolved := ctx.resolved()
+	if user, exists := resolved.Users[interaction.Data.TargetID]; exists {
+		ctx.Target = &user
+	}
+	ctx.Member, _ = resolveMember(resolved, interaction.Data.TargetID)
+
+	if ctx.Target == nil {
+		ih.bot.logger.Warnf("User command %s has no resolved target", interaction.Data.Name)
+		return
+	}
+
+	ih.startAutoDefer(ctx.Response, types.InteractionResponseTypeDeferredChannelMessageWithSource)
+	ih.bot.runCommand(ctx.CommandContext, func() { handler(ctx) })
+}
+
+func (ih *InteractionHandler) handleMessageCommand(interaction *types.Interaction) {
+	ih.commandMutex.Lock()
+	handler, exists := ih.msgCommands[interaction.Data.Name]
+	ih.commandMutex.Unlock()
+
+	if !exists {
+		ih.bot.logger.Warnf("No handler found for message command: %s", interaction.Data.Name)
+		return
+	}
+
+	ctx := &MessageCommandContext{
+		CommandContext: newCommandContext(interaction, ih.bot, interaction.Data.Name, nil),
+	}
+
+	message, exists := ctx.resolved().Messages[interaction.Data.TargetID]
+	if !exists {
+		ih.bot.logger.Warnf("Message command %s has no resolved target", interaction.Data.Name)
+		return
+	}
+	ctx.Target = &message
+
+	ih.startAutoDefer(ctx.Response, types.InteractionResponseTypeDeferredChannelMessageWithSource)
+	ih.bot.runCommand(ctx.CommandContext, func() { handler(ctx) })
+}
@@ -0,0 +1,323 @@
+package bot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MaxCustomIDLength is the longest custom_id discord accepts
+const MaxCustomIDLength = 100
+
+var (
+	ErrCustomIDTooLong  = errors.New("custom ID is longer than 100 characters")
+	ErrInvalidSignature = errors.New("custom ID signature is invalid")
+)
+
+type componentKind int
+
+const (
+	componentButton componentKind = iota
+	componentSelectMenu
+	componentModal
+)
+
+// customIDPattern is a compiled custom ID route like "delete:{id}" or "page:*"
+type customIDPattern struct {
+	kind     componentKind
+	pattern  string
+	segments []patternSegment
+	handler  interface{}
+}
+
+// patternSegment is either literal text or a named parameter
+type patternSegment struct {
+	literal string
+	param   string
+}
+
+// isPattern reports whether a custom ID has parameters or a trailing wildcard
+func isPattern(customID string) bool {
+	return strings.Contains(customID, "{") || strings.HasSuffix(customID, "*")
+}
+
+// compilePattern splits a pattern into literals and {params}. A trailing *
+// matches any remainder, which is available as the "*" param.
+func compilePattern(pattern string) ([]patternSegment, error) {
+	var segments []patternSegment
+	rest := pattern
+
+	for rest != "" {
+		if rest == "*" {
+			if n := len(segments); n > 0 && segments[n-1].param != "" {
+				return nil, fmt.Errorf("a parameter can't be followed by * in custom ID pattern %q", pattern)
+			}
+			segments = append(segments, patternSegment{param: "*"})
+			break
+		}
+
+		start := strings.IndexByte(rest, '{')
+		if start == -1 {
+			literal := rest
+			if strings.HasSuffix(literal, "*") {
+				literal = strings.TrimSuffix(literal, "*")
+				segments = append(segments, patternSegment{literal: literal}, patternSegment{param: "*"})
+			} else {
+				segments = append(segments, patternSegment{literal: literal})
+			}
+			break
+		}
+		if start > 0 {
+			segments = append(segments, patternSegment{literal: rest[:start]})
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end == -1 {
+			return nil, fmt.Errorf("unclosed parameter in custom ID pattern %q", pattern)
+		}
+		name := rest[start+1 : start+end]
+		if name == "" {
+			return nil, fmt.Errorf("empty parameter name in custom ID pattern %q", pattern)
+		}
+		if n := len(segments); n > 0 && segments[n-1].param != "" {
+			return nil, fmt.Errorf("parameters must be separated by text in custom ID pattern %q", pattern)
+		}
+
+		segments = append(segments, patternSegment{param: name})
+		rest = rest[start+end+1:]
+	}
+
+	return segments, nil
+}
+
+// match returns the params of customID, or false if it doesn't fit the pattern.
+// A parameter extends up to the next occurrence of the literal after it.
+func (p *customIDPattern) match(customID string) (map[string]string, bool) {
+	params := make(map[string]string)
+	rest := customID
+
+	for i, segment := range p.segments {
+		if segment.param == "" {
+			if !strings.HasPrefix(rest, segment.literal) {
+				return nil, false
+			}
+			rest = rest[len(segment.literal):]
+			continue
+		}
+
+		if segment.param == "*" || i == len(p.segments)-1 {
+			if rest == "" && segment.param != "*" {
+				return nil, false
+			}
+			params[segment.param] = rest
+			rest = ""
+			continue
+		}
+
+		end := strings.Index(rest, p.segments[i+1].literal)
+		if end <= 0 {
+			return nil, false
+		}
+		params[segment.param] = rest[:end]
+		rest = rest[end:]
+	}
+
+	if rest != "" {
+		return nil, false
+	}
+	return params, true
+}
+
+// addPattern registers a pattern route. Callers must hold componentMutex.
+func (ih *InteractionHandler) addPattern(kind componentKind, pattern string, handler interface{}) {
+	segments, err := compilePattern(pattern)
+	if err != nil {
+		ih.bot.logger.Errorf("%v", err)
+		return
+	}
+
+	for _, existing := range ih.patterns {
+		if existing.kind == kind && existing.pattern == pattern {
+			existing.handler = handler
+			return
+		}
+	}
+
+	ih.patterns = append(ih.patterns, &customIDPattern{
+		kind:     kind,
+		pattern:  pattern,
+		segments: segments,
+		handler:  handler,
+	})
+}
+
+// lookupComponent finds the handler for a custom ID. Exact IDs win over
+// patterns, and patterns are tried in registration order.
+func (ih *InteractionHandler) lookupComponent(kind componentKind, customID string) (interface{}, map[string]string, bool) {
+	ih.componentMutex.Lock()
+	defer ih.componentMutex.Unlock()
+
+	var handler interface{}
+	var exists bool
+	switch kind {
+	case componentButton:
+		handler, exists = ih.buttons[customID]
+	case componentSelectMenu:
+		handler, exists = ih.selectMenus[customID]
+	case componentModal:
+		handler, exists = ih.modals[customID]
+	}
+	if exists {
+		return handler, map[string]string{}, true
+	}
+
+	for _, pattern := range ih.patterns {
+		if pattern.kind != kind {
+			continue
+		}
+		if params, ok := pattern.match(customID); ok {
+			return pattern.handler, params, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+// Param returns a parameter captured by the custom ID pattern
+func (ctx *ComponentContext) Param(name string) string {
+	return ctx.Params[name]
+}
+
+// Param returns a parameter captured by the custom ID pattern
+func (ctx *ModalContext) Param(name string) string {
+	return ctx.Params[name]
+}
+
+// CustomIDCodec packs small values into a custom ID as "prefix:payload",
+// where payload is base64 JSON. Struct fields are stored by position instead
+// of by name to save space, so the struct layout must not change while old
+// messages are still around.
+//
+// With a secret the payload is followed by a truncated HMAC-SHA256, so
+// Decode rejects IDs that were modified by the client.
+type CustomIDCodec struct {
+	secret []byte
+}
+
+const customIDSignatureSize = 8
+
+func NewCustomIDCodec(secret ...[]byte) *CustomIDCodec {
+	codec := &CustomIDCodec{}
+	if len(secret) > 0 {
+		codec.secret = secret[0]
+	}
+	return codec
+}
+
+// Encode returns prefix + ":" + the encoded state. The prefix can be matched
+// with a route like "prefix:*".
+func (c *CustomIDCodec) Encode(prefix string, state interface{}) (string, error) {
+	data, err := marshalState(state)
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	if c.secret != nil {
+		payload += "." + base64.RawURLEncoding.EncodeToString(c.sign(prefix, data))
+	}
+
+	customID := payload
+	if prefix != "" {
+		customID = prefix + ":" + payload
+	}
+	if len(customID) > MaxCustomIDLength {
+		return "", ErrCustomIDTooLong
+	}
+	return customID, nil
+}
+
+// Decode reads the state written by Encode into dst, which must be a pointer
+func (c *CustomIDCodec) Decode(customID string, dst interface{}) error {
+	prefix, payload := "", customID
+	if i := strings.LastIndexByte(customID, ':'); i != -1 {
+		prefix, payload = customID[:i], customID[i+1:]
+	}
+
+	encoded, signature, signed := strings.Cut(payload, ".")
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("failed to decode custom ID state: %w", err)
+	}
+
+	if c.secret != nil {
+		if !signed {
+			return ErrInvalidSignature
+		}
+		mac, err := base64.RawURLEncoding.DecodeString(signature)
+		if err != nil || !hmac.Equal(mac, c.sign(prefix, data)) {
+			return ErrInvalidSignature
+		}
+	}
+
+	return unmarshalState(data, dst)
+}
+
+func (c *CustomIDCodec) sign(prefix string, data []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(prefix))
+	mac.Write([]byte{0})
+	mac.Write(data)
+	return mac.Sum(nil)[:customIDSignatureSize]
+}
+
+func marshalState(state interface{}) ([]byte, error) {
+	v := reflect.ValueOf(state)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return json.Marshal(state)
+	}
+
+	fields := make([]interface{}, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).IsExported() {
+			fields = append(fields, v.Field(i).Interface())
+		}
+	}
+	return json.Marshal(fields)
+}
+
+func unmarshalState(data []byte, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("custom ID state must be decoded into a pointer")
+	}
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return json.Unmarshal(data, dst)
+	}
+
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("failed to decode custom ID state: %w", err)
+	}
+
+	n := 0
+	for i := 0; i < v.NumField() && n < len(fields); i++ {
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+		if err := json.Unmarshal(fields[n], v.Field(i).Addr().Interface()); err != nil {
+			return fmt.Errorf("failed to decode custom ID field %s: %w", v.Type().Field(i).Name, err)
+		}
+		n++
+	}
+	return nil
+}
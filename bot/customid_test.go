@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCustomIDPatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		customID string
+		params   map[string]string
+		ok       bool
+	}{
+		{"delete:{id}", "delete:5", map[string]string{"id": "5"}, true},
+		{"a:{x}:b:{y}", "a:1:b:2", map[string]string{"x": "1", "y": "2"}, true},
+		{"page:*", "page:3:next", map[string]string{"*": "3:next"}, true},
+		{"{kind}:ban", "user:ban", map[string]string{"kind": "user"}, true},
+		{"delete:{id}", "remove:5", nil, false},
+		{"delete:{id}", "delete:", nil, false},
+		{"a:{x}:b", "a:1:c", nil, false},
+		{"page:*", "pages", nil, false},
+	}
+
+	for _, test := range tests {
+		segments, err := compilePattern(test.pattern)
+		if err != nil {
+			t.Fatalf("compilePattern(%q): %v", test.pattern, err)
+		}
+		pattern := &customIDPattern{segments: segments}
+
+		params, ok := pattern.match(test.customID)
+		if ok != test.ok {
+			t.Errorf("%q against %q: matched = %v, want %v", test.customID, test.pattern, ok, test.ok)
+			continue
+		}
+		if ok && !reflect.DeepEqual(params, test.params) {
+			t.Errorf("%q against %q: params = %v, want %v", test.customID, test.pattern, params, test.params)
+		}
+	}
+}
+
+func TestCompilePatternErrors(t *testing.T) {
+	for _, pattern := range []string{
+		"x:{id}*",
+		"{a}{b}",
+		"x:{id",
+		"x:{}",
+	} {
+		if _, err := compilePattern(pattern); err == nil {
+			t.Errorf("compilePattern(%q) succeeded, want an error", pattern)
+		}
+	}
+}
+
+type testState struct {
+	UserID string
+	Page   int
+}
+
+func TestCustomIDCodecRoundTrip(t *testing.T) {
+	for _, codec := range []*CustomIDCodec{NewCustomIDCodec(), NewCustomIDCodec([]byte("secret"))} {
+		customID, err := codec.Encode("page", testState{UserID: "123", Page: 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(customID, "page:") {
+			t.Fatalf("custom ID %q doesn't start with the prefix", customID)
+		}
+
+		var state testState
+		if err := codec.Decode(customID, &state); err != nil {
+			t.Fatalf("Decode(%q): %v", customID, err)
+		}
+		if state != (testState{UserID: "123", Page: 4}) {
+			t.Fatalf("decoded state = %+v", state)
+		}
+	}
+}
+
+func TestCustomIDCodecRejectsTampering(t *testing.T) {
+	codec := NewCustomIDCodec([]byte("secret"))
+	customID, err := codec.Encode("page", testState{UserID: "123", Page: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := codec.Encode("page", testState{UserID: "456", Page: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, signature, _ := strings.Cut(strings.TrimPrefix(customID, "page:"), ".")
+	otherPayload, _, _ := strings.Cut(strings.TrimPrefix(other, "page:"), ".")
+
+	tests := map[string]string{
+		"swapped payload":   "page:" + otherPayload + "." + signature,
+		"missing signature": "page:" + payload,
+		"cut signature":     "page:" + payload + "." + signature[:len(signature)-2],
+		"other prefix":      "next:" + payload + "." + signature,
+		"wrong secret":      mustEncode(t, NewCustomIDCodec([]byte("other")), testState{UserID: "123", Page: 4}),
+	}
+	for name, customID := range tests {
+		var state testState
+		if err := codec.Decode(customID, &state); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: err = %v, want ErrInvalidSignature", name, err)
+		}
+	}
+}
+
+func TestCustomIDCodecRejectsCorruptState(t *testing.T) {
+	codec := NewCustomIDCodec()
+	customID, err := codec.Encode("page", testState{UserID: "123", Page: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, corrupt := range []string{
+		customID[:len(customID)-3],
+		"page:!!!",
+		"page",
+	} {
+		var state testState
+		if err := codec.Decode(corrupt, &state); err == nil {
+			t.Errorf("Decode(%q) succeeded, want an error", corrupt)
+		}
+	}
+}
+
+func mustEncode(t *testing.T, codec *CustomIDCodec, state interface{}) string {
+	t.Helper()
+
+	customID, err := codec.Encode("page", state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return customID
+}
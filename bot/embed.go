@@ -0,0 +1,242 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nyrilol/discord-go/api"
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// Colors of discord's brand palette
+const (
+	ColorBlurple = 0x5865F2
+	ColorGreen   = 0x57F287
+	ColorYellow  = 0xFEE75C
+	ColorFuchsia = 0xEB459E
+	ColorRed     = 0xED4245
+	ColorWhite   = 0xFFFFFF
+	ColorBlack   = 0x23272A
+)
+
+// RGB packs red, green and blue into an embed color
+func RGB(r, g, b uint8) int {
+	return int(r)<<16 | int(g)<<8 | int(b)
+}
+
+// EmbedBuilder builds an embed step by step:
+//
+//	embed, err := bot.NewEmbed().
+//		SetTitle("Stats").
+//		SetColor(bot.ColorBlurple).
+//		AddField("Users", "42", true).
+//		SetTimestamp(time.Now()).
+//		Build()
+//
+// Build reports every limit the embed exceeds.
+type EmbedBuilder struct {
+	embed types.Embed
+	errs  []string
+}
+
+func NewEmbed() *EmbedBuilder {
+	return &EmbedBuilder{}
+}
+
+func (b *EmbedBuilder) SetTitle(title string) *EmbedBuilder {
+	b.embed.Title = title
+	return b
+}
+
+func (b *EmbedBuilder) SetDescription(description string) *EmbedBuilder {
+	b.embed.Description = description
+	return b
+}
+
+func (b *EmbedBuilder) SetURL(url string) *EmbedBuilder {
+	b.embed.URL = url
+	return b
+}
+
+func (b *EmbedBuilder) SetColor(color int) *EmbedBuilder {
+	b.embed.Color = color
+	return b
+}
+
+// SetColorHex sets the color from a string like "#5865F2" or "5865f2"
+func (b *EmbedBuilder) SetColorHex(hex string) *EmbedBuilder {
+	color, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil || color > 0xFFFFFF {
+		b.errs = append(b.errs, fmt.Sprintf("invalid hex color %q", hex))
+		return b
+	}
+	b.embed.Color = int(color)
+	return b
+}
+
+func (b *EmbedBuilder) SetTimestamp(t time.Time) *EmbedBuilder {
+	b.embed.Timestamp = t.UTC().Format(time.RFC3339)
+	return b
+}
+
+func (b *EmbedBuilder) SetFooter(text, iconURL string) *EmbedBuilder {
+	b.embed.Footer = &types.EmbedFooter{Text: text, IconURL: iconURL}
+	return b
+}
+
+func (b *EmbedBuilder) SetAuthor(name, url, iconURL string) *EmbedBuilder {
+	b.embed.Author = &types.EmbedAuthor{Name: name, URL: url, IconURL: iconURL}
+	return b
+}
+
+func (b *EmbedBuilder) SetImage(url string) *EmbedBuilder {
+	b.embed.Image = &types.EmbedImage{URL: url}
+	return b
+}
+
+func (b *EmbedBuilder) SetThumbnail(url string) *EmbedBuilder {
+	b.embed.Thumbnail = &types.EmbedThumbnail{URL: url}
+	return b
+}
+
+func (b *EmbedBuilder) AddField(name, value string, inline ...bool) *EmbedBuilder {
+	b.embed.Fields = append(b.embed.Fields, types.EmbedField{
+		Name:   name,
+		Value:  value,
+		Inline: len(inline) > 0 && inline[0],
+	})
+	return b
+}
+
+// Build returns the embed, or an *api.EmbedLimitError listing every problem
+func (b *EmbedBuilder) Build() (*types.Embed, error) {
+	embed := b.embed
+	embed.Fields = append([]types.EmbedField(nil), b.embed.Fields...)
+
+	err := api.ValidateEmbed(embed)
+	if len(b.errs) > 0 {
+		limitErr, _ := err.(*api.EmbedLimitError)
+		if limitErr == nil {
+			limitErr = &api.EmbedLimitError{}
+		}
+		limitErr.Violations = append(append([]string(nil), b.errs...), limitErr.Violations...)
+		err = limitErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &embed, nil
+}
+
+// MustBuild is Build for embeds known to be valid; it panics on errors
+func (b *EmbedBuilder) MustBuild() *types.Embed {
+	embed, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return embed
+}
+
+// SplitText cuts text into chunks of at most limit characters, preferring to
+// break at newlines, then spaces, and only cutting words that don't fit alone.
+func SplitText(text string, limit int) []string {
+	if limit <= 0 {
+		return nil
+	}
+
+	var chunks []string
+	for utf8.RuneCountInString(text) > limit {
+		cut := runeOffset(text, limit)
+		window := text[:cut]
+
+		end := strings.LastIndexByte(window, '\n')
+		if end <= 0 {
+			end = strings.LastIndexByte(window, ' ')
+		}
+		next := end + 1
+		if end <= 0 {
+			end, next = cut, cut
+		}
+
+		chunks = append(chunks, text[:end])
+		text = text[next:]
+	}
+
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
+
+// runeOffset returns the byte offset of the n-th rune
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
+
+// SplitMessage cuts content into messages that fit discord's 2000 character limit
+func SplitMessage(content string) []string {
+	return SplitText(content, api.MaxMessageLength)
+}
+
+// SplitEmbed spreads a long text over as many embeds as needed, using base for
+// the styling. The title and author are kept on the first embed and the footer
+// and timestamp on the last, so the result reads as one long embed. Send the
+// result with GroupEmbeds to stay within the per-message limits.
+func SplitEmbed(base types.Embed, text string) []*types.Embed {
+	chunks := SplitText(text, api.MaxEmbedDescriptionLength)
+	if len(chunks) == 0 {
+		chunks = []string{""}
+	}
+
+	embeds := make([]*types.Embed, len(chunks))
+	for i, chunk := range chunks {
+		embed := base
+		embed.Description = chunk
+		if i > 0 {
+			embed.Title = ""
+			embed.URL = ""
+			embed.Author = nil
+			embed.Thumbnail = nil
+		}
+		if i < len(chunks)-1 {
+			embed.Footer = nil
+			embed.Timestamp = ""
+			embed.Fields = nil
+			embed.Image = nil
+		}
+		embeds[i] = &embed
+	}
+	return embeds
+}
+
+// GroupEmbeds packs embeds into as few messages as possible, each holding at
+// most 10 embeds and 6000 characters.
+func GroupEmbeds(embeds []*types.Embed) [][]*types.Embed {
+	var groups [][]*types.Embed
+	var current []*types.Embed
+	length := 0
+
+	for _, embed := range embeds {
+		n := api.EmbedLength(*embed)
+		if len(current) > 0 && (len(current) == api.MaxEmbedsPerMessage || length+n > api.MaxEmbedTotalLength) {
+			groups = append(groups, current)
+			current, length = nil, 0
+		}
+		current = append(current, embed)
+		length += n
+	}
+
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
@@ -0,0 +1,33 @@
+package bot
+
+//go:generate go run ../internal/genevents
+
+import (
+	"strings"
+
+	"github.com/nyrilol/discord-go/api/types"
+	"github.com/nyrilol/discord-go/gateway"
+)
+
+// On registers a typed handler for the event T stands for, e.g.
+//
+//	bot.On(b, func(e *types.MessageCreateEvent) { ... })
+//
+// The payload is decoded once and shared between all handlers of the same
+// type, so handlers must not modify it. The OnXxx methods are shorthands.
+func On[T types.Event](bot *Bot, handler func(*T)) *gateway.Subscription {
+	var event T
+	return gateway.AddHandler(bot.gateway, event.EventName(), handler)
+}
+
+// OnceEvent is On for a handler that only runs for the next event
+func OnceEvent[T types.Event](bot *Bot, handler func(*T)) *gateway.Subscription {
+	var event T
+	return gateway.AddOnceHandler(bot.gateway, event.EventName(), handler)
+}
+
+// OnEvent registers a typed handler for an event by name, for events without
+// a struct in api/types or to decode into a custom type.
+func OnEvent[T any](bot *Bot, eventName string, handler func(*T)) *gateway.Subscription {
+	return gateway.AddHandler(bot.gateway, strings.ToUpper(eventName), handler)
+}
@@ -0,0 +1,308 @@
+// Code generated by internal/genevents; DO NOT EDIT.
+
+package bot
+
+import (
+	"github.com/nyrilol/discord-go/api/types"
+	"github.com/nyrilol/discord-go/gateway"
+)
+
+// OnMessageCreate registers a handler for MESSAGE_CREATE events
+func (bot *Bot) OnMessageCreate(handler func(*types.MessageCreateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnMessageUpdate registers a handler for MESSAGE_UPDATE events
+func (bot *Bot) OnMessageUpdate(handler func(*types.MessageUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnMessageDelete registers a handler for MESSAGE_DELETE events
+func (bot *Bot) OnMessageDelete(handler func(*types.MessageDeleteEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnMessageDeleteBulk registers a handler for MESSAGE_DELETE_BULK events
+func (bot *Bot) OnMessageDeleteBulk(handler func(*types.MessageDeleteBulkEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnMessageReactionAdd registers a handler for MESSAGE_REACTION_ADD events
+func (bot *Bot) OnMessageReactionAdd(handler func(*types.MessageReactionAddEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnMessageReactionRemove registers a handler for MESSAGE_REACTION_REMOVE events
+func (bot *Bot) OnMessageReactionRemove(handler func(*types.MessageReactionRemoveEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnMessageReactionRemoveAll registers a handler for MESSAGE_REACTION_REMOVE_ALL events
+func (bot *Bot) OnMessageReactionRemoveAll(handler func(*types.MessageReactionRemoveAllEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnMessageReactionRemoveEmoji registers a handler for MESSAGE_REACTION_REMOVE_EMOJI events
+func (bot *Bot) OnMessageReactionRemoveEmoji(handler func(*types.MessageReactionRemoveEmojiEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildCreate registers a handler for GUILD_CREATE events
+func (bot *Bot) OnGuildCreate(handler func(*types.GuildCreateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildUpdate registers a handler for GUILD_UPDATE events
+func (bot *Bot) OnGuildUpdate(handler func(*types.GuildUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildDelete registers a handler for GUILD_DELETE events
+func (bot *Bot) OnGuildDelete(handler func(*types.GuildDeleteEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildMemberAdd registers a handler for GUILD_MEMBER_ADD events
+func (bot *Bot) OnGuildMemberAdd(handler func(*types.GuildMemberAddEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildMemberUpdate registers a handler for GUILD_MEMBER_UPDATE events
+func (bot *Bot) OnGuildMemberUpdate(handler func(*types.GuildMemberUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildMemberRemove registers a handler for GUILD_MEMBER_REMOVE events
+func (bot *Bot) OnGuildMemberRemove(handler func(*types.GuildMemberRemoveEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildRoleCreate registers a handler for GUILD_ROLE_CREATE events
+func (bot *Bot) OnGuildRoleCreate(handler func(*types.GuildRoleCreateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildRoleUpdate registers a handler for GUILD_ROLE_UPDATE events
+func (bot *Bot) OnGuildRoleUpdate(handler func(*types.GuildRoleUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildRoleDelete registers a handler for GUILD_ROLE_DELETE events
+func (bot *Bot) OnGuildRoleDelete(handler func(*types.GuildRoleDeleteEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnChannelCreate registers a handler for CHANNEL_CREATE events
+func (bot *Bot) OnChannelCreate(handler func(*types.ChannelCreateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnChannelUpdate registers a handler for CHANNEL_UPDATE events
+func (bot *Bot) OnChannelUpdate(handler func(*types.ChannelUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnChannelDelete registers a handler for CHANNEL_DELETE events
+func (bot *Bot) OnChannelDelete(handler func(*types.ChannelDeleteEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnChannelPinsUpdate registers a handler for CHANNEL_PINS_UPDATE events
+func (bot *Bot) OnChannelPinsUpdate(handler func(*types.ChannelPinsUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnThreadCreate registers a handler for THREAD_CREATE events
+func (bot *Bot) OnThreadCreate(handler func(*types.ThreadCreateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnThreadUpdate registers a handler for THREAD_UPDATE events
+func (bot *Bot) OnThreadUpdate(handler func(*types.ThreadUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnThreadDelete registers a handler for THREAD_DELETE events
+func (bot *Bot) OnThreadDelete(handler func(*types.ThreadDeleteEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnThreadListSync registers a handler for THREAD_LIST_SYNC events
+func (bot *Bot) OnThreadListSync(handler func(*types.ThreadListSyncEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnThreadMemberUpdate registers a handler for THREAD_MEMBER_UPDATE events
+func (bot *Bot) OnThreadMemberUpdate(handler func(*types.ThreadMemberUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnVoiceStateUpdate registers a handler for VOICE_STATE_UPDATE events
+func (bot *Bot) OnVoiceStateUpdate(handler func(*types.VoiceStateUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnVoiceServerUpdate registers a handler for VOICE_SERVER_UPDATE events
+func (bot *Bot) OnVoiceServerUpdate(handler func(*types.VoiceServerUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnPresenceUpdate registers a handler for PRESENCE_UPDATE events
+func (bot *Bot) OnPresenceUpdate(handler func(*types.PresenceUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnUserUpdate registers a handler for USER_UPDATE events
+func (bot *Bot) OnUserUpdate(handler func(*types.UserUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnReady registers a handler for READY events
+func (bot *Bot) OnReady(handler func(*types.ReadyEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnApplicationCommandCreate registers a handler for APPLICATION_COMMAND_CREATE events
+func (bot *Bot) OnApplicationCommandCreate(handler func(*types.ApplicationCommandCreateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnApplicationCommandUpdate registers a handler for APPLICATION_COMMAND_UPDATE events
+func (bot *Bot) OnApplicationCommandUpdate(handler func(*types.ApplicationCommandUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnApplicationCommandDelete registers a handler for APPLICATION_COMMAND_DELETE events
+func (bot *Bot) OnApplicationCommandDelete(handler func(*types.ApplicationCommandDeleteEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnInteractionCreate registers a handler for INTERACTION_CREATE events
+func (bot *Bot) OnInteractionCreate(handler func(*types.InteractionCreateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnInviteCreate registers a handler for INVITE_CREATE events
+func (bot *Bot) OnInviteCreate(handler func(*types.InviteCreateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnInviteDelete registers a handler for INVITE_DELETE events
+func (bot *Bot) OnInviteDelete(handler func(*types.InviteDeleteEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnStageInstanceCreate registers a handler for STAGE_INSTANCE_CREATE events
+func (bot *Bot) OnStageInstanceCreate(handler func(*types.StageInstanceCreateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnStageInstanceUpdate registers a handler for STAGE_INSTANCE_UPDATE events
+func (bot *Bot) OnStageInstanceUpdate(handler func(*types.StageInstanceUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnStageInstanceDelete registers a handler for STAGE_INSTANCE_DELETE events
+func (bot *Bot) OnStageInstanceDelete(handler func(*types.StageInstanceDeleteEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildScheduledEventCreate registers a handler for GUILD_SCHEDULED_EVENT_CREATE events
+func (bot *Bot) OnGuildScheduledEventCreate(handler func(*types.GuildScheduledEventCreateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildScheduledEventUpdate registers a handler for GUILD_SCHEDULED_EVENT_UPDATE events
+func (bot *Bot) OnGuildScheduledEventUpdate(handler func(*types.GuildScheduledEventUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildScheduledEventDelete registers a handler for GUILD_SCHEDULED_EVENT_DELETE events
+func (bot *Bot) OnGuildScheduledEventDelete(handler func(*types.GuildScheduledEventDeleteEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildScheduledEventUserAdd registers a handler for GUILD_SCHEDULED_EVENT_USER_ADD events
+func (bot *Bot) OnGuildScheduledEventUserAdd(handler func(*types.GuildScheduledEventUserAddEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildScheduledEventUserRemove registers a handler for GUILD_SCHEDULED_EVENT_USER_REMOVE events
+func (bot *Bot) OnGuildScheduledEventUserRemove(handler func(*types.GuildScheduledEventUserRemoveEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnAutoModerationRuleCreate registers a handler for AUTO_MODERATION_RULE_CREATE events
+func (bot *Bot) OnAutoModerationRuleCreate(handler func(*types.AutoModerationRuleCreateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnAutoModerationRuleUpdate registers a handler for AUTO_MODERATION_RULE_UPDATE events
+func (bot *Bot) OnAutoModerationRuleUpdate(handler func(*types.AutoModerationRuleUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnAutoModerationRuleDelete registers a handler for AUTO_MODERATION_RULE_DELETE events
+func (bot *Bot) OnAutoModerationRuleDelete(handler func(*types.AutoModerationRuleDeleteEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnAutoModerationActionExecution registers a handler for AUTO_MODERATION_ACTION_EXECUTION events
+func (bot *Bot) OnAutoModerationActionExecution(handler func(*types.AutoModerationActionExecutionEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnTypingStart registers a handler for TYPING_START events
+func (bot *Bot) OnTypingStart(handler func(*types.TypingStartEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnWebhooksUpdate registers a handler for WEBHOOKS_UPDATE events
+func (bot *Bot) OnWebhooksUpdate(handler func(*types.WebhooksUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnIntegrationCreate registers a handler for INTEGRATION_CREATE events
+func (bot *Bot) OnIntegrationCreate(handler func(*types.IntegrationCreateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnIntegrationUpdate registers a handler for INTEGRATION_UPDATE events
+func (bot *Bot) OnIntegrationUpdate(handler func(*types.IntegrationUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnIntegrationDelete registers a handler for INTEGRATION_DELETE events
+func (bot *Bot) OnIntegrationDelete(handler func(*types.IntegrationDeleteEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnEntitlementCreate registers a handler for ENTITLEMENT_CREATE events
+func (bot *Bot) OnEntitlementCreate(handler func(*types.EntitlementCreateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnEntitlementUpdate registers a handler for ENTITLEMENT_UPDATE events
+func (bot *Bot) OnEntitlementUpdate(handler func(*types.EntitlementUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnEntitlementDelete registers a handler for ENTITLEMENT_DELETE events
+func (bot *Bot) OnEntitlementDelete(handler func(*types.EntitlementDeleteEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildJoinRequestCreate registers a handler for GUILD_JOIN_REQUEST_CREATE events
+func (bot *Bot) OnGuildJoinRequestCreate(handler func(*types.GuildJoinRequestCreateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildJoinRequestUpdate registers a handler for GUILD_JOIN_REQUEST_UPDATE events
+func (bot *Bot) OnGuildJoinRequestUpdate(handler func(*types.GuildJoinRequestUpdateEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
+
+// OnGuildJoinRequestDelete registers a handler for GUILD_JOIN_REQUEST_DELETE events
+func (bot *Bot) OnGuildJoinRequestDelete(handler func(*types.GuildJoinRequestDeleteEvent)) *gateway.Subscription {
+	return On(bot, handler)
+}
@@ -0,0 +1,248 @@
+package bot
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+const maxInteractionBodySize = 1 << 20
+
+// InteractionServer is an http.Handler for discord's outgoing-webhook interactions
+// (the "Interactions Endpoint URL"). It verifies the request signature and
+// dispatches through the same routes as gateway interactions.
+//
+// By default the first response a handler sends is written to the HTTP body
+// instead of being POSTed to the callback endpoint.
+type InteractionServer struct {
+	handler   *InteractionHandler
+	publicKey ed25519.PublicKey
+
+	// ResponseTimeout is how long to wait for the initial response before
+	// answering with a deferred response. Discord gives up after 3 seconds.
+	ResponseTimeout time.Duration
+
+	// UseCallbacks answers the HTTP request with 202 right away and lets the
+	// handlers respond through the callback endpoint instead.
+	UseCallbacks bool
+
+	// MaxTimestampAge rejects requests whose signed timestamp is further than
+	// this from now, so a captured request can't be replayed. Zero disables it.
+	MaxTimestampAge time.Duration
+}
+
+// pendingResponse is an HTTP request waiting for an interaction's initial
+// response. It stays registered until the handlers return, so every responder
+// created for the interaction is the one stored here.
+type pendingResponse struct {
+	body      chan []byte
+	responder *InteractionResponder
+	claimed   atomic.Bool // set once the HTTP body is spoken for
+}
+
+// ParsePublicKey decodes the hex public key shown on the developer portal
+func ParsePublicKey(key string) (ed25519.PublicKey, error) {
+	decoded, err := hex.DecodeString(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) != ed25519.PublicKeySize {
+		return nil, errors.New("public key must be 32 bytes")
+	}
+	return ed25519.PublicKey(decoded), nil
+}
+
+func NewInteractionServer(ih *InteractionHandler, publicKey ed25519.PublicKey) *InteractionServer {
+	return &InteractionServer{
+		handler:         ih,
+		publicKey:       publicKey,
+		ResponseTimeout: 2500 * time.Millisecond,
+		MaxTimestampAge: 5 * time.Minute,
+	}
+}
+
+// VerifyInteraction checks the X-Signature-Ed25519 header against timestamp + body
+func VerifyInteraction(publicKey ed25519.PublicKey, signature, timestamp string, body []byte) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize || timestamp == "" {
+		return false
+	}
+
+	message := make([]byte, 0, len(timestamp)+len(body))
+	message = append(message, timestamp...)
+	message = append(message, body...)
+
+	return ed25519.Verify(publicKey, message, sig)
+}
+
+func (s *InteractionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxInteractionBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	timestamp := r.Header.Get("X-Signature-Timestamp")
+	if !VerifyInteraction(s.publicKey, r.Header.Get("X-Signature-Ed25519"), timestamp, body) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+	if s.MaxTimestampAge > 0 && !timestampFresh(timestamp, s.MaxTimestampAge) {
+		http.Error(w, "stale request timestamp", http.StatusUnauthorized)
+		return
+	}
+
+	var interaction types.Interaction
+	if err := json.Unmarshal(body, &interaction); err != nil {
+		http.Error(w, "invalid interaction", http.StatusBadRequest)
+		return
+	}
+
+	if interaction.Type == types.InteractionTypePing {
+		writeJSON(w, types.InteractionResponse{Type: types.InteractionResponseTypePong})
+		return
+	}
+
+	if s.UseCallbacks {
+		go s.handler.handleInteraction(interaction)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	bot := s.handler.bot
+	pending := &pendingResponse{
+		body:      make(chan []byte, 1),
+		responder: newInteractionResponder(bot, &interaction),
+	}
+	bot.pending.Store(interaction.ID, pending)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer bot.pending.Delete(interaction.ID)
+		s.handler.handleInteraction(interaction)
+	}()
+
+	timer := time.NewTimer(s.ResponseTimeout)
+	defer timer.Stop()
+
+	select {
+	case response := <-pending.body:
+		writeRawJSON(w, response)
+		return
+	case <-done:
+	case <-timer.C:
+	}
+
+	// the handler finished or is too slow; take the slot back unless it
+	// responded in the meantime, and acknowledge with a deferred response.
+	// Holding the responder lock keeps it from responding while we decide.
+	pending.responder.mu.Lock()
+	defer pending.responder.mu.Unlock()
+
+	if !pending.claimed.CompareAndSwap(false, true) {
+		writeRawJSON(w, <-pending.body)
+		return
+	}
+	pending.responder.markDeferred(deferTypeFor(interaction))
+
+	bot.logger.Warnf("No initial response for interaction %s, deferring", interaction.ID)
+	writeJSON(w, deferredResponseFor(interaction))
+}
+
+// timestampFresh checks a unix seconds timestamp against the current time
+func timestampFresh(timestamp string, maxAge time.Duration) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := time.Since(time.Unix(seconds, 0))
+	return age <= maxAge && age >= -maxAge
+}
+
+// deliverPending hands an initial response to a waiting HTTP request.
+// It returns false when the interaction didn't come in over HTTP.
+func (bot *Bot) deliverPending(interactionID types.Snowflake, response interface{}) bool {
+	value, ok := bot.pending.Load(interactionID)
+	if !ok || !value.(*pendingResponse).claimed.CompareAndSwap(false, true) {
+		return false
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		bot.logger.Errorf("Failed to encode interaction response: %v", err)
+		data = []byte(`{"type":5}`)
+	}
+
+	value.(*pendingResponse).body <- data
+	return true
+}
+
+func (bot *Bot) sendAutocompleteResult(interactionID types.Snowflake, token string, choices []types.ApplicationCommandOptionChoice) error {
+	if choices == nil {
+		choices = []types.ApplicationCommandOptionChoice{}
+	}
+
+	payload := map[string]interface{}{
+		"type": types.InteractionResponseTypeApplicationCommandAutocompleteResult,
+		"data": map[string]interface{}{
+			"choices": choices,
+		},
+	}
+	if bot.deliverPending(interactionID, payload) {
+		return nil
+	}
+
+	return bot.gateway.SendAutocompleteResult(interactionID, token, choices)
+}
+
+func deferredResponseFor(interaction types.Interaction) interface{} {
+	if interaction.Type == types.InteractionTypeApplicationCommandAutocomplete {
+		return map[string]interface{}{
+			"type": types.InteractionResponseTypeApplicationCommandAutocompleteResult,
+			"data": map[string]interface{}{"choices": []types.ApplicationCommandOptionChoice{}},
+		}
+	}
+	return types.InteractionResponse{Type: deferTypeFor(interaction)}
+}
+
+// deferTypeFor picks the defer that fits an interaction: components (and modals
+// opened from one) keep their message, everything else gets a "thinking..." message
+func deferTypeFor(interaction types.Interaction) int {
+	switch {
+	case interaction.Type == types.InteractionTypeMessageComponent:
+		return types.InteractionResponseTypeDeferredUpdateMessage
+	case interaction.Type == types.InteractionTypeModalSubmit && interaction.Message != nil:
+		return types.InteractionResponseTypeDeferredUpdateMessage
+	default:
+		return types.InteractionResponseTypeDeferredChannelMessageWithSource
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	writeRawJSON(w, data)
+}
+
+func writeRawJSON(w http.ResponseWriter, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,145 @@
+package bot
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+func newTestServer(t *testing.T) (*InteractionServer, *InteractionHandler, ed25519.PrivateKey) {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ih := NewInteractionHandler(NewBot("test.bot.token"))
+	return NewInteractionServer(ih, publicKey), ih, privateKey
+}
+
+func signedRequest(key ed25519.PrivateKey, timestamp time.Time, body string) *http.Request {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	signature := ed25519.Sign(key, []byte(ts+body))
+
+	req := httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewBufferString(body))
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(signature))
+	req.Header.Set("X-Signature-Timestamp", ts)
+	return req
+}
+
+func decodeResponse(t *testing.T, rec *httptest.ResponseRecorder) types.InteractionResponse {
+	t.Helper()
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %q)", rec.Code, rec.Body.String())
+	}
+	var response types.InteractionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid response body %q: %v", rec.Body.String(), err)
+	}
+	return response
+}
+
+func TestInteractionServerPing(t *testing.T) {
+	server, _, key := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, signedRequest(key, time.Now(), `{"id":"1","type":1}`))
+
+	if response := decodeResponse(t, rec); response.Type != types.InteractionResponseTypePong {
+		t.Fatalf("response type = %d, want PONG", response.Type)
+	}
+}
+
+func TestInteractionServerRejectsInvalidSignatures(t *testing.T) {
+	server, _, key := newTestServer(t)
+	_, otherKey, _ := ed25519.GenerateKey(nil)
+	body := `{"id":"1","type":1}`
+
+	missing := httptest.NewRequest(http.MethodPost, "/interactions", bytes.NewBufferString(body))
+
+	wrongKey := signedRequest(otherKey, time.Now(), body)
+
+	tampered := signedRequest(key, time.Now(), body)
+	tampered.Body = io.NopCloser(bytes.NewBufferString(`{"id":"2","type":1}`))
+
+	tests := map[string]*http.Request{
+		"missing headers": missing,
+		"wrong key":       wrongKey,
+		"tampered body":   tampered,
+	}
+	for name, req := range tests {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, want 401", name, rec.Code)
+		}
+	}
+}
+
+func TestInteractionServerRejectsStaleTimestamp(t *testing.T) {
+	server, _, key := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, signedRequest(key, time.Now().Add(-time.Hour), `{"id":"1","type":1}`))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", rec.Code)
+	}
+}
+
+func TestInteractionServerInlineResponse(t *testing.T) {
+	server, ih, key := newTestServer(t)
+	ih.Command("ping", func(ctx *CommandContext) {
+		if err := ctx.Respond("pong"); err != nil {
+			t.Errorf("Respond: %v", err)
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	body := `{"id":"1","application_id":"2","type":2,"token":"token","data":{"name":"ping","type":1}}`
+	server.ServeHTTP(rec, signedRequest(key, time.Now(), body))
+
+	response := decodeResponse(t, rec)
+	if response.Type != types.InteractionResponseTypeChannelMessageWithSource {
+		t.Fatalf("response type = %d, want a channel message", response.Type)
+	}
+	if response.Data == nil || response.Data.Content != "pong" {
+		t.Fatalf("response data = %+v, want content pong", response.Data)
+	}
+}
+
+func TestInteractionServerDefersSlowHandlers(t *testing.T) {
+	server, ih, key := newTestServer(t)
+	server.ResponseTimeout = 20 * time.Millisecond
+
+	release := make(chan struct{})
+	responders := make(chan *InteractionResponder, 1)
+	ih.Command("slow", func(ctx *CommandContext) {
+		responders <- ctx.Response
+		<-release
+	})
+
+	rec := httptest.NewRecorder()
+	body := `{"id":"1","application_id":"2","type":2,"token":"token","data":{"name":"slow","type":1}}`
+	server.ServeHTTP(rec, signedRequest(key, time.Now(), body))
+	defer close(release)
+
+	response := decodeResponse(t, rec)
+	if response.Type != types.InteractionResponseTypeDeferredChannelMessageWithSource {
+		t.Fatalf("response type = %d, want a deferred message", response.Type)
+	}
+	if responder := <-responders; !responder.Acknowledged() {
+		t.Fatal("the handler's responder doesn't know about the deferred HTTP answer")
+	}
+}
@@ -0,0 +1,805 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nyrilol/discord-go/api"
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// Context is what a hybrid command's handler gets, whether the command was run
+// as a slash command or as a prefix command. Options are read by name either way.
+type Context interface {
+	Bot() *Bot
+	Command() string
+	IsSlash() bool
+
+	Author() *types.User
+	AuthorMember() *types.GuildMember // nil outside guilds
+	GuildID() string
+	ChannelID() string
+	Guild() *types.Guild // from the cache, nil outside guilds or when not cached
+
+	// Reply answers the command; ephemeral only applies to slash commands
+	Reply(content string, ephemeral ...bool) error
+	ReplyMessage(message types.WebhookMessage) error
+
+	String(name string) (string, bool)
+	Int(name string) (int64, bool)
+	Float(name string) (float64, bool)
+	Bool(name string) (bool, bool)
+	User(name string) (*types.User, bool)
+	Member(name string) (*types.GuildMember, bool)
+	Role(name string) (*types.Role, bool)
+	Channel(name string) (*types.Channel, bool)
+	Attachment(name string) (*types.Attachment, bool)
+	Mentionable(name string) (*Mentionable, bool)
+	Err() error
+
+	Set(key string, value interface{})
+	Get(key string) (interface{}, bool)
+
+	Slash() *CommandContext    // nil for prefix commands
+	Text() *TextCommandContext // nil for slash commands
+}
+
+// Check runs before a hybrid command. The message of a returned error is
+// shown to the user and the command doesn't run.
+type Check func(ctx Context) error
+
+// HybridMiddleware wraps hybrid commands on both transports. Call next to run
+// the checks and the handler, or return without calling it to stop the command.
+type HybridMiddleware func(ctx Context, next func() error) error
+
+// HybridCommand is defined once and served as a slash command and as a prefix
+// command. For the prefix command the options are read in order; a string
+// option at the end takes the rest of the message, and attachment options take
+// the message's attachments.
+//
+// Middlewares added with Bot.UseCommand only wrap the slash command, since
+// they take a *CommandContext. Use HybridCommands.Use for middlewares that
+// apply to both, such as permission checks.
+type HybridCommand struct {
+	Name        string
+	Description string
+	Options     []types.ApplicationCommandOption
+	Aliases     []string // prefix command only
+	Category    string   // prefix command help
+	Checks      []Check
+	GuildOnly   bool
+	Handler     func(ctx Context) error
+}
+
+// NewHybridCommand builds a hybrid command whose options come from the fields
+// of T, tagged like for TypedCommand:
+//
+//	cmd, err := bot.NewHybridCommand("ban", "Ban a member", func(ctx bot.Context, args BanArgs) error {
+//		return ctx.Reply("Banned " + args.User.Username)
+//	})
+//	cmd.Checks = append(cmd.Checks, bot.RequirePermissions(permBanMembers))
+//	commands.Add(cmd)
+func NewHybridCommand[T any](name, description string, handler func(ctx Context, args T) error) (*HybridCommand, error) {
+	spec, err := structSpecFor(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	return &HybridCommand{
+		Name:        name,
+		Description: description,
+		Options:     spec.options(),
+		Handler: func(ctx Context) error {
+			var args T
+			if err := spec.decode(ctx, reflect.ValueOf(&args).Elem()); err != nil {
+				return err
+			}
+			return handler(ctx, args)
+		},
+	}, nil
+}
+
+// HybridCommands serves hybrid commands through an interaction handler and a
+// text command router. Either may be nil to serve only one kind.
+//
+//	commands := bot.NewHybridCommands(ih, bot.NewTextCommandRouter(b, "!"))
+//	commands.Add(&bot.HybridCommand{Name: "ping", Description: "Pong!", Handler: ping})
+//	commands.Register()
+type HybridCommands struct {
+	ih     *InteractionHandler
+	router *TextCommandRouter
+
+	mu          sync.Mutex
+	commands    []*HybridCommand
+	middlewares []HybridMiddleware
+}
+
+func NewHybridCommands(ih *InteractionHandler, router *TextCommandRouter) *HybridCommands {
+	return &HybridCommands{ih: ih, router: router}
+}
+
+// Add serves a command. Register (or SyncCommands) still has to be called to
+// create the slash command on discord.
+func (h *HybridCommands) Add(command *HybridCommand) error {
+	if strings.ContainsAny(command.Name, " \t\n") {
+		return fmt.Errorf("hybrid command names can't contain spaces: %q", command.Name)
+	}
+	if err := api.ValidateApplicationCommand(command.definition()); err != nil {
+		return err
+	}
+
+	if h.ih != nil {
+		err := h.ih.Command(command.Name, func(ctx *CommandContext) {
+			h.runSlash(command, ctx)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	h.mu.Lock()
+	h.commands = append(h.commands, command)
+	h.mu.Unlock()
+
+	if h.router != nil {
+		h.router.Command(command.Name, func(ctx *TextCommandContext) error {
+			return h.runText(command, ctx)
+		},
+			WithAliases(command.Aliases...),
+			WithTextDescription(command.Description),
+			WithUsage(textUsage(command.Options)),
+			WithCategory(command.Category))
+	}
+	return nil
+}
+
+// Use adds a middleware that runs around every hybrid command, slash and
+// prefix alike, before its checks
+func (h *HybridCommands) Use(middleware HybridMiddleware) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.middlewares = append(h.middlewares, middleware)
+}
+
+// Definitions returns the slash command definitions of the added commands
+func (h *HybridCommands) Definitions() []types.ApplicationCommand {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	definitions := make([]types.ApplicationCommand, len(h.commands))
+	for i, command := range h.commands {
+		definitions[i] = command.definition()
+	}
+	return definitions
+}
+
+// Register creates the slash commands, globally or in one guild
+func (h *HybridCommands) Register(guildID ...types.Snowflake) error {
+	if h.ih == nil {
+		return fmt.Errorf("hybrid commands have no interaction handler")
+	}
+	for _, definition := range h.Definitions() {
+		if err := h.ih.RegisterCommand(definition, guildID...); err != nil {
+			return fmt.Errorf("failed to register %s: %w", definition.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c *HybridCommand) definition() types.ApplicationCommand {
+	definition := types.ApplicationCommand{
+		Type:        types.ApplicationCommandTypeChatInput,
+		Name:        c.Name,
+		Description: c.Description,
+		Options:     c.Options,
+	}
+	if c.GuildOnly {
+		definition.Contexts = []int{types.InteractionContextTypeGuild}
+	}
+	return definition
+}
+
+// run passes the command through the middlewares, then applies the checks and
+// calls the handler. Check failures are answered here; the handler's error is
+// returned.
+func (h *HybridCommands) run(command *HybridCommand, ctx Context) error {
+	h.mu.Lock()
+	middlewares := h.middlewares
+	h.mu.Unlock()
+
+	chain := func() error {
+		if command.GuildOnly && ctx.GuildID() == "" {
+			return ctx.Reply("This command only works in servers.", true)
+		}
+		for _, check := range command.Checks {
+			if err := check(ctx); err != nil {
+				return ctx.Reply(capitalize(err.Error()), true)
+			}
+		}
+		return command.Handler(ctx)
+	}
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		mw := middlewares[i]
+		next := chain
+		chain = func() error { return mw(ctx, next) }
+	}
+	return chain()
+}
+
+// runSlash runs a slash command and answers its error when the handler
+// didn't send anything, so the user isn't left on "thinking..."
+func (h *HybridCommands) runSlash(command *HybridCommand, ctx *CommandContext) {
+	err := h.run(command, &slashContext{ctx: ctx})
+	if err == nil {
+		return
+	}
+
+	content := "Something went wrong while running this command."
+	var argErr *ArgumentError
+	if errors.As(err, &argErr) {
+		content = capitalize(argErr.Error())
+	} else {
+		ctx.Bot.logger.Errorf("Command %s failed: %v", command.Name, err)
+	}
+
+	if ctx.Response.answered() {
+		return
+	}
+	if err := ctx.Respond(content, true); err != nil {
+		ctx.Bot.logger.Errorf("Failed to answer command %s: %v", command.Name, err)
+	}
+}
+
+func (h *HybridCommands) runText(command *HybridCommand, ctx *TextCommandContext) error {
+	values, err := parseTextOptions(ctx, command.Options)
+	if err != nil {
+		return err
+	}
+	return h.run(command, &textContext{ctx: ctx, options: command.Options, values: values})
+}
+
+// textUsage describes the options for the prefix command's help:
+// "<user> [days] [reason...]"
+func textUsage(options []types.ApplicationCommandOption) string {
+	var parts []string
+	for i, option := range options {
+		name := option.Name
+		if option.Type == types.ApplicationCommandOptionTypeAttachment {
+			name += " (attachment)"
+		} else if i == len(options)-1 && option.Type == types.ApplicationCommandOptionTypeString {
+			name += "..."
+		}
+		if option.Required {
+			parts = append(parts, "<"+name+">")
+		} else {
+			parts = append(parts, "["+name+"]")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// parseTextOptions converts the arguments of a prefix command into option
+// values, checking them the way discord checks slash command options
+func parseTextOptions(ctx *TextCommandContext, options []types.ApplicationCommandOption) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(options))
+	position, attachments := 0, 0
+
+	for i, option := range options {
+		if option.Type == types.ApplicationCommandOptionTypeAttachment {
+			if attachments < len(ctx.Message.Attachments) {
+				values[option.Name] = &ctx.Message.Attachments[attachments]
+				attachments++
+			} else if option.Required {
+				return nil, &ArgumentError{Index: position, Name: option.Name, Reason: "needs an attachment"}
+			}
+			continue
+		}
+
+		index := position
+		position++
+		if index >= len(ctx.Args) {
+			if option.Required {
+				return nil, &ArgumentError{Index: index, Name: option.Name, Reason: "is missing"}
+			}
+			continue
+		}
+
+		value, err := parseTextOption(ctx, option, index, i == len(options)-1)
+		if err != nil {
+			var argErr *ArgumentError
+			if errors.As(err, &argErr) {
+				argErr.Name = option.Name
+			}
+			return nil, err
+		}
+		values[option.Name] = value
+	}
+	return values, nil
+}
+
+func parseTextOption(ctx *TextCommandContext, option types.ApplicationCommandOption, index int, last bool) (interface{}, error) {
+	arg := ctx.Arg(index)
+	invalid := func(reason string) error {
+		return &ArgumentError{Index: index, Value: arg, Reason: reason}
+	}
+
+	switch option.Type {
+	case types.ApplicationCommandOptionTypeString:
+		// a trailing string takes the rest of the message as typed, unless
+		// that's a single (possibly quoted) argument
+		value := arg
+		if last && index < len(ctx.Args)-1 {
+			value = ctx.Rest(index)
+		}
+		if option.MinLength != nil && len([]rune(value)) < *option.MinLength {
+			return nil, invalid(fmt.Sprintf("must be at least %d characters", *option.MinLength))
+		}
+		if option.MaxLength != nil && len([]rune(value)) > *option.MaxLength {
+			return nil, invalid(fmt.Sprintf("must be at most %d characters", *option.MaxLength))
+		}
+		return matchChoice(option, value, invalid)
+
+	case types.ApplicationCommandOptionTypeInteger, types.ApplicationCommandOptionTypeNumber:
+		if len(option.Choices) > 0 {
+			return matchChoice(option, arg, invalid)
+		}
+
+		var value interface{}
+		var n float64
+		if option.Type == types.ApplicationCommandOptionTypeInteger {
+			i, err := ctx.Int(index)
+			if err != nil {
+				return nil, err
+			}
+			value, n = i, float64(i)
+		} else {
+			f, err := ctx.Float(index)
+			if err != nil {
+				return nil, err
+			}
+			value, n = f, f
+		}
+		if option.MinValue != nil && n < *option.MinValue {
+			return nil, invalid(fmt.Sprintf("must be at least %v", *option.MinValue))
+		}
+		if option.MaxValue != nil && n > *option.MaxValue {
+			return nil, invalid(fmt.Sprintf("must be at most %v", *option.MaxValue))
+		}
+		return value, nil
+
+	case types.ApplicationCommandOptionTypeBoolean:
+		switch strings.ToLower(arg) {
+		case "true", "yes", "y", "on", "1", "enable", "enabled":
+			return true, nil
+		case "false", "no", "n", "off", "0", "disable", "disabled":
+			return false, nil
+		}
+		return nil, invalid("is not yes or no")
+
+	case types.ApplicationCommandOptionTypeUser:
+		user, err := ctx.User(index)
+		if err != nil {
+			return nil, err
+		}
+		return user, nil
+
+	case types.ApplicationCommandOptionTypeChannel:
+		channel, err := ctx.Channel(index)
+		if err != nil {
+			return nil, err
+		}
+		if len(option.ChannelTypes) > 0 {
+			allowed := false
+			for _, channelType := range option.ChannelTypes {
+				allowed = allowed || channel.Type == channelType
+			}
+			if !allowed {
+				return nil, invalid("is not the right kind of channel")
+			}
+		}
+		return channel, nil
+
+	case types.ApplicationCommandOptionTypeRole:
+		role, err := ctx.Role(index)
+		if err != nil {
+			return nil, err
+		}
+		return role, nil
+
+	case types.ApplicationCommandOptionTypeMentionable:
+		if user, err := ctx.User(index); err == nil {
+			return &Mentionable{User: user, Member: textMember(ctx, user.ID)}, nil
+		}
+		if role, err := ctx.Role(index); err == nil {
+			return &Mentionable{Role: role}, nil
+		}
+		return nil, invalid("is not a known user or role")
+	}
+
+	return nil, invalid("has an unsupported type")
+}
+
+// matchChoice returns the value of the choice arg names (by name or value), or
+// arg itself when the option has no choices
+func matchChoice(option types.ApplicationCommandOption, arg string, invalid func(string) error) (interface{}, error) {
+	if len(option.Choices) == 0 {
+		return arg, nil
+	}
+
+	names := make([]string, len(option.Choices))
+	for i, choice := range option.Choices {
+		if strings.EqualFold(choice.Name, arg) || strings.EqualFold(fmt.Sprint(choice.Value), arg) {
+			switch value := choice.Value.(type) {
+			case int:
+				return int64(value), nil
+			case float64:
+				if option.Type == types.ApplicationCommandOptionTypeInteger {
+					return int64(value), nil
+				}
+			}
+			return choice.Value, nil
+		}
+		names[i] = choice.Name
+	}
+	return nil, invalid("must be one of " + strings.Join(names, ", "))
+}
+
+func textMember(ctx *TextCommandContext, userID string) *types.GuildMember {
+	if ctx.Message.GuildID == "" {
+		return nil
+	}
+	if member, exists := ctx.Bot.gateway.Cache().Member(ctx.Message.GuildID, userID); exists {
+		return &member
+	}
+	return nil
+}
+
+// slashContext serves a hybrid command run as a slash command
+type slashContext struct {
+	ctx *CommandContext
+}
+
+func (c *slashContext) Bot() *Bot                          { return c.ctx.Bot }
+func (c *slashContext) Command() string                    { return c.ctx.Command }
+func (c *slashContext) IsSlash() bool                      { return true }
+func (c *slashContext) GuildID() string                    { return c.ctx.Interaction.GuildID }
+func (c *slashContext) ChannelID() string                  { return c.ctx.Interaction.ChannelID }
+func (c *slashContext) Slash() *CommandContext             { return c.ctx }
+func (c *slashContext) Text() *TextCommandContext          { return nil }
+func (c *slashContext) Err() error                         { return c.ctx.Err() }
+func (c *slashContext) Set(key string, value interface{})  { c.ctx.Set(key, value) }
+func (c *slashContext) Get(key string) (interface{}, bool) { return c.ctx.Get(key) }
+
+func (c *slashContext) Author() *types.User {
+	if c.ctx.Interaction.Member != nil {
+		return &c.ctx.Interaction.Member.User
+	}
+	return c.ctx.Interaction.User
+}
+
+func (c *slashContext) AuthorMember() *types.GuildMember {
+	return c.ctx.Interaction.Member
+}
+
+func (c *slashContext) Guild() *types.Guild {
+	return cachedGuild(c.ctx.Bot, c.GuildID())
+}
+
+func (c *slashContext) Reply(content string, ephemeral ...bool) error {
+	return c.ctx.Respond(content, ephemeral...)
+}
+
+func (c *slashContext) ReplyMessage(message types.WebhookMessage) error {
+	return c.ctx.Response.Respond(types.InteractionCallbackData{
+		TTS:             message.TTS,
+		Content:         message.Content,
+		Embeds:          message.Embeds,
+		AllowedMentions: message.AllowedMentions,
+		Flags:           message.Flags,
+		Components:      message.Components,
+		Attachments:     message.Attachments,
+	})
+}
+
+func (c *slashContext) String(name string) (string, bool) { return c.ctx.String(name) }
+func (c *slashContext) Int(name string) (int64, bool)     { return c.ctx.Int(name) }
+func (c *slashContext) Float(name string) (float64, bool) { return c.ctx.Float(name) }
+func (c *slashContext) Bool(name string) (bool, bool)     { return c.ctx.Bool(name) }
+func (c *slashContext) User(name string) (*types.User, bool) {
+	return c.ctx.User(name)
+}
+func (c *slashContext) Member(name string) (*types.GuildMember, bool) {
+	return c.ctx.Member(name)
+}
+func (c *slashContext) Role(name string) (*types.Role, bool) {
+	return c.ctx.Role(name)
+}
+func (c *slashContext) Channel(name string) (*types.Channel, bool) {
+	return c.ctx.Channel(name)
+}
+func (c *slashContext) Attachment(name string) (*types.Attachment, bool) {
+	return c.ctx.Attachment(name)
+}
+func (c *slashContext) Mentionable(name string) (*Mentionable, bool) {
+	return c.ctx.Mentionable(name)
+}
+
+// textContext serves a hybrid command run as a prefix command, with the
+// arguments already converted by parseTextOptions
+type textContext struct {
+	ctx       *TextCommandContext
+	options   []types.ApplicationCommandOption
+	values    map[string]interface{}
+	optionErr error
+	data      map[string]interface{}
+}
+
+func (c *textContext) Bot() *Bot                 { return c.ctx.Bot }
+func (c *textContext) Command() string           { return c.ctx.Command.Name }
+func (c *textContext) IsSlash() bool             { return false }
+func (c *textContext) GuildID() string           { return c.ctx.Message.GuildID }
+func (c *textContext) ChannelID() string         { return c.ctx.Message.ChannelID }
+func (c *textContext) Slash() *CommandContext    { return nil }
+func (c *textContext) Text() *TextCommandContext { return c.ctx }
+func (c *textContext) Err() error                { return c.optionErr }
+func (c *textContext) Author() *types.User       { return &c.ctx.Message.Author }
+
+func (c *textContext) AuthorMember() *types.GuildMember {
+	if c.ctx.Message.Member == nil {
+		return nil
+	}
+	member := *c.ctx.Message.Member
+	member.User = c.ctx.Message.Author
+	return &member
+}
+
+func (c *textContext) Guild() *types.Guild {
+	return cachedGuild(c.ctx.Bot, c.GuildID())
+}
+
+func (c *textContext) Reply(content string, ephemeral ...bool) error {
+	_, err := c.ctx.Reply(content)
+	return err
+}
+
+func (c *textContext) ReplyMessage(message types.WebhookMessage) error {
+	message.Flags &^= types.MessageFlagEphemeral
+	_, err := c.ctx.ReplyMessage(message)
+	return err
+}
+
+func (c *textContext) Set(key string, value interface{}) {
+	if c.data == nil {
+		c.data = make(map[string]interface{})
+	}
+	c.data[key] = value
+}
+
+func (c *textContext) Get(key string) (interface{}, bool) {
+	value, exists := c.data[key]
+	return value, exists
+}
+
+// value returns an option's value, recording a type mismatch like CommandContext does
+func (c *textContext) value(name, expected string, optionTypes ...int) (interface{}, bool) {
+	value, exists := c.values[name]
+	if !exists {
+		return nil, false
+	}
+
+	for _, option := range c.options {
+		if option.Name != name {
+			continue
+		}
+		for _, optionType := range optionTypes {
+			if option.Type == optionType {
+				return value, true
+			}
+		}
+		if c.optionErr == nil {
+			c.optionErr = &OptionTypeError{Option: name, Expected: expected, Actual: option.Type}
+		}
+	}
+	return nil, false
+}
+
+func (c *textContext) String(name string) (string, bool) {
+	value, ok := c.value(name, "string", types.ApplicationCommandOptionTypeString)
+	s, _ := value.(string)
+	return s, ok
+}
+
+func (c *textContext) Int(name string) (int64, bool) {
+	value, ok := c.value(name, "integer", types.ApplicationCommandOptionTypeInteger)
+	i, _ := value.(int64)
+	return i, ok
+}
+
+func (c *textContext) Float(name string) (float64, bool) {
+	value, ok := c.value(name, "number", types.ApplicationCommandOptionTypeNumber, types.ApplicationCommandOptionTypeInteger)
+	switch n := value.(type) {
+	case float64:
+		return n, ok
+	case int64:
+		return float64(n), ok
+	}
+	return 0, false
+}
+
+func (c *textContext) Bool(name string) (bool, bool) {
+	value, ok := c.value(name, "boolean", types.ApplicationCommandOptionTypeBoolean)
+	b, _ := value.(bool)
+	return b, ok
+}
+
+func (c *textContext) User(name string) (*types.User, bool) {
+	value, ok := c.value(name, "user", types.ApplicationCommandOptionTypeUser, types.ApplicationCommandOptionTypeMentionable)
+	switch v := value.(type) {
+	case *types.User:
+		return v, ok
+	case *Mentionable:
+		return v.User, ok && v.User != nil
+	}
+	return nil, false
+}
+
+func (c *textContext) Member(name string) (*types.GuildMember, bool) {
+	user, ok := c.User(name)
+	if !ok {
+		return nil, false
+	}
+	member := textMember(c.ctx, user.ID)
+	return member, member != nil
+}
+
+func (c *textContext) Role(name string) (*types.Role, bool) {
+	value, ok := c.value(name, "role", types.ApplicationCommandOptionTypeRole, types.ApplicationCommandOptionTypeMentionable)
+	switch v := value.(type) {
+	case *types.Role:
+		return v, ok
+	case *Mentionable:
+		return v.Role, ok && v.Role != nil
+	}
+	return nil, false
+}
+
+func (c *textContext) Channel(name string) (*types.Channel, bool) {
+	value, ok := c.value(name, "channel", types.ApplicationCommandOptionTypeChannel)
+	channel, _ := value.(*types.Channel)
+	return channel, ok
+}
+
+func (c *textContext) Attachment(name string) (*types.Attachment, bool) {
+	value, ok := c.value(name, "attachment", types.ApplicationCommandOptionTypeAttachment)
+	attachment, _ := value.(*types.Attachment)
+	return attachment, ok
+}
+
+func (c *textContext) Mentionable(name string) (*Mentionable, bool) {
+	value, ok := c.value(name, "mentionable", types.ApplicationCommandOptionTypeMentionable, types.ApplicationCommandOptionTypeUser, types.ApplicationCommandOptionTypeRole)
+	switch v := value.(type) {
+	case *Mentionable:
+		return v, ok
+	case *types.User:
+		return &Mentionable{User: v, Member: textMember(c.ctx, v.ID)}, ok
+	case *types.Role:
+		return &Mentionable{Role: v}, ok
+	}
+	return nil, false
+}
+
+func cachedGuild(bot *Bot, guildID string) *types.Guild {
+	if guildID == "" {
+		return nil
+	}
+	guild, exists := bot.gateway.Cache().Guild(guildID)
+	if !exists {
+		return nil
+	}
+	return &guild
+}
+
+// RequireUsers only lets the given users run the command, e.g. the bot's owners
+func RequireUsers(userIDs ...string) Check {
+	return func(ctx Context) error {
+		author := ctx.Author()
+		for _, id := range userIDs {
+			if author != nil && author.ID == id {
+				return nil
+			}
+		}
+		return fmt.Errorf("you can't use this command")
+	}
+}
+
+// RequireRoles only lets members with at least one of the roles run the command
+func RequireRoles(roleIDs ...string) Check {
+	return func(ctx Context) error {
+		member := ctx.AuthorMember()
+		if member == nil {
+			return fmt.Errorf("this command only works in servers")
+		}
+		for _, has := range member.Roles {
+			for _, id := range roleIDs {
+				if has == id {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("you don't have a role that can use this command")
+	}
+}
+
+// permissionAdministrator grants every permission
+const permissionAdministrator = 1 << 3
+
+// RequirePermissions only lets members with all of the permission bits run
+// the command. Slash commands use the permissions discord sends; prefix
+// commands compute them from the cached roles, without channel overwrites.
+func RequirePermissions(permissions int64) Check {
+	return func(ctx Context) error {
+		if ctx.GuildID() == "" {
+			return fmt.Errorf("this command only works in servers")
+		}
+
+		have, ok := memberPermissions(ctx)
+		if !ok {
+			return fmt.Errorf("couldn't check your permissions")
+		}
+		if have&permissionAdministrator != 0 || have&permissions == permissions {
+			return nil
+		}
+		return fmt.Errorf("you don't have the permissions to use this command")
+	}
+}
+
+func memberPermissions(ctx Context) (int64, bool) {
+	member := ctx.AuthorMember()
+	if member == nil {
+		return 0, false
+	}
+
+	if member.Permissions != "" {
+		permissions, err := strconv.ParseInt(member.Permissions, 10, 64)
+		return permissions, err == nil
+	}
+
+	guild := ctx.Guild()
+	if guild == nil {
+		return 0, false
+	}
+	if guild.OwnerID == member.User.ID {
+		return -1, true // owners can do anything
+	}
+
+	var permissions int64
+	for _, role := range guild.Roles {
+		if role.ID != guild.ID && !containsString(member.Roles, role.ID) {
+			continue
+		}
+		permissions |= rolePermissions(role)
+	}
+	return permissions, true
+}
+
+// rolePermissions reads a role's permissions, which discord sends as a string
+func rolePermissions(role types.Role) int64 {
+	var raw interface{}
+	if err := json.Unmarshal(role.Permissions, &raw); err != nil {
+		return 0
+	}
+	switch v := raw.(type) {
+	case string:
+		permissions, _ := strconv.ParseInt(v, 10, 64)
+		return permissions
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
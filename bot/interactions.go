@@ -1,19 +1,27 @@
 package bot
 
 import (
+	"github.com/nyrilol/discord-go/api"
 	"github.com/nyrilol/discord-go/api/types"
 	"fmt"
+	"strings"
 )
 
 func NewInteractionHandler(bot *Bot) *InteractionHandler {
 	return &InteractionHandler{
 		bot:            bot,
 		commands:       make(map[string]CommandHandler),
+		userCommands:   make(map[string]UserCommandHandler),
+		msgCommands:    make(map[string]MessageCommandHandler),
 		buttons:        make(map[string]ButtonHandler),
 		selectMenus:    make(map[string]SelectMenuHandler),
 		modals:         make(map[string]ModalHandler),
-		globalCommands: make(map[string]types.ApplicationCommand),
-		guildCommands:  make(map[types.Snowflake]map[string]types.ApplicationCommand),
+		autocompletes:  make(map[autocompleteKey]AutocompleteHandler),
+		paginators:     make(map[string]*Paginator),
+		globalCommands: make(map[commandKey]types.ApplicationCommand),
+		guildCommands:  make(map[types.Snowflake]map[commandKey]types.ApplicationCommand),
+		syncedGuilds:   make(map[types.Snowflake]bool),
+		placeholders:   make(map[commandKey]bool),
 	}
 }
 
@@ -29,49 +37,55 @@ func (ih *InteractionHandler) handleInteraction(interaction types.Interaction) {
 		ih.handleComponent(&interaction)
 	case types.InteractionTypeModalSubmit:
 		ih.handleModal(&interaction)
+	case types.InteractionTypeApplicationCommandAutocomplete:
+		ih.handleAutocomplete(&interaction)
 	default:
 		ih.bot.logger.Warnf("Unhandled interaction type: %d", interaction.Type)
 	}
 }
 
 func (ih *InteractionHandler) handleCommand(interaction *types.Interaction) {
-	commandName := interaction.Data.Name
-	handler, exists := ih.commands[commandName]
-	if !exists {
-		ih.bot.logger.Warnf("No handler found for command: %s", commandName)
+	switch interaction.Data.Type {
+	case types.ApplicationCommandTypeUser:
+		ih.handleUserCommand(interaction)
+		return
+	case types.ApplicationCommandTypeMessage:
+		ih.handleMessageCommand(interaction)
 		return
 	}
 
-	ctx := &CommandContext{
-		Interaction: interaction,
-		Bot:         ih.bot,
-		Options:     make(map[string]interface{}),
-	}
+	path, options := resolveCommandPath(interaction.Data)
 
-	if interaction.Data.Options != nil {
-		for _, option := range interaction.Data.Options {
-			ctx.Options[option.Name] = option.Value
-		}
+	ih.commandMutex.Lock()
+	handler, exists := ih.lookupCommand(path)
+	ih.commandMutex.Unlock()
+
+	if !exists {
+		ih.bot.logger.Warnf("No handler found for command: %s", path)
+		return
 	}
 
-	handler(ctx)
+	ctx := newCommandContext(interaction, ih.bot, path, options)
+	ih.startAutoDefer(ctx.Response, types.InteractionResponseTypeDeferredChannelMessageWithSource)
+	ih.bot.runCommand(ctx, func() { handler(ctx) })
 }
 
 func (ih *InteractionHandler) handleComponent(interaction *types.Interaction) {
 	customID := interaction.Data.CustomID
-	var handler interface{}
-	var exists bool
+	var kind componentKind
 
 	switch interaction.Data.ComponentType {
 	case types.ComponentTypeButton:
-		handler, exists = ih.buttons[customID]
-	case types.ComponentTypeSelectMenu:
-		handler, exists = ih.selectMenus[customID]
+		kind = componentButton
+	case types.ComponentTypeSelectMenu, types.ComponentTypeUserSelect, types.ComponentTypeRoleSelect,
+		types.ComponentTypeMentionableSelect, types.ComponentTypeChannelSelect:
+		kind = componentSelectMenu
 	default:
 		ih.bot.logger.Warnf("Unhandled component type: %d", interaction.Data.ComponentType)
 		return
 	}
 
+	handler, params, exists := ih.lookupComponent(kind, customID)
 	if !exists {
 		ih.bot.logger.Warnf("No handler found for component with custom ID: %s", customID)
 		return
@@ -81,8 +95,11 @@ func (ih *InteractionHandler) handleComponent(interaction *types.Interaction) {
 		Interaction: interaction,
 		Bot:         ih.bot,
 		CustomID:    customID,
+		Params:      params,
 		Values:      interaction.Data.Values,
+		Response:    newInteractionResponder(ih.bot, interaction),
 	}
+	ih.startAutoDefer(ctx.Response, deferTypeFor(*interaction))
 
 	switch h := handler.(type) {
 	case ButtonHandler:
@@ -94,119 +111,135 @@ func (ih *InteractionHandler) handleComponent(interaction *types.Interaction) {
 
 func (ih *InteractionHandler) handleModal(interaction *types.Interaction) {
 	customID := interaction.Data.CustomID
-	handler, exists := ih.modals[customID]
+	value, params, exists := ih.lookupComponent(componentModal, customID)
 	if !exists {
 		ih.bot.logger.Warnf("No handler found for modal with custom ID: %s", customID)
 		return
 	}
+	handler := value.(ModalHandler)
 
-	inputs := make(map[string]string)
-	for _, row := range interaction.Data.Components {
-		actionRow, ok := row.(types.ActionRowComponent)
-		if !ok {
-			continue
-		}
-
-		for _, component := range actionRow.Components {
-			if input, ok := component.(types.TextInputComponent); ok {
-				inputs[input.CustomID] = input.Value
-			}
-		}
-	}
+	inputs, selects := modalInputs(interaction.Data.Components)
 
 	ctx := &ModalContext{
 		Interaction: interaction,
 		Bot:         ih.bot,
 		CustomID:    customID,
+		Params:      params,
 		Inputs:      inputs,
+		Selects:     selects,
+		Response:    newInteractionResponder(ih.bot, interaction),
 	}
+	ih.startAutoDefer(ctx.Response, deferTypeFor(*interaction))
 
 	handler(ctx)
 }
 
-func (ih *InteractionHandler) Command(name string, handler CommandHandler) {
+// Command registers a handler for a command. name can be a full path like
+// "config set prefix" to route a subcommand, in which case the matching
+// subcommand (group) options are also added to the command's definition.
+// Route options only apply to subcommands; a top-level command's options are
+// part of the definition passed to RegisterCommand.
+func (ih *InteractionHandler) Command(name string, handler CommandHandler, options ...RouteOption) error {
+	path := normalizeCommandPath(name)
+	parts := strings.Split(path, " ")
+	if len(parts) > 3 {
+		return fmt.Errorf("command path %q is too deep, discord allows a command, group and subcommand", path)
+	}
+	if len(parts) == 1 && len(options) > 0 {
+		return fmt.Errorf("route options only apply to subcommands, define the options of %q in RegisterCommand", path)
+	}
+
 	ih.commandMutex.Lock()
 	defer ih.commandMutex.Unlock()
-	ih.commands[name] = handler
+
+	ih.commands[path] = handler
+	if len(parts) == 1 {
+		return nil
+	}
+
+	route := &commandRoute{path: parts}
+	for _, opt := range options {
+		opt(route)
+	}
+	ih.addRoute(route)
+	ih.applyRouteToRegistered(route)
+	return nil
 }
 
+// Button registers a handler for a button. customID is either an exact ID or
+// a pattern: "delete:{id}" captures ctx.Param("id"), and a trailing * matches
+// any suffix ("page:*"), captured as ctx.Param("*"). Exact IDs are tried first.
 func (ih *InteractionHandler) Button(customID string, handler ButtonHandler) {
 	ih.componentMutex.Lock()
 	defer ih.componentMutex.Unlock()
+	if isPattern(customID) {
+		ih.addPattern(componentButton, customID, handler)
+		return
+	}
 	ih.buttons[customID] = handler
 }
 
+// SelectMenu registers a handler for a select menu, see Button for patterns
 func (ih *InteractionHandler) SelectMenu(customID string, handler SelectMenuHandler) {
 	ih.componentMutex.Lock()
 	defer ih.componentMutex.Unlock()
+	if isPattern(customID) {
+		ih.addPattern(componentSelectMenu, customID, handler)
+		return
+	}
 	ih.selectMenus[customID] = handler
 }
 
+// Modal registers a handler for a modal submit, see Button for patterns
 func (ih *InteractionHandler) Modal(customID string, handler ModalHandler) {
 	ih.componentMutex.Lock()
 	defer ih.componentMutex.Unlock()
+	if isPattern(customID) {
+		ih.addPattern(componentModal, customID, handler)
+		return
+	}
 	ih.modals[customID] = handler
 }
 
+// Respond sends content as the initial response. After a defer it edits the
+// deferred message instead, and after that it sends followups.
 func (ctx *CommandContext) Respond(content string, ephemeral ...bool) error {
-	return ctx.respond(types.InteractionResponseTypeChannelMessageWithSource, content, ephemeral...)
+	return ctx.Response.Respond(types.InteractionCallbackData{
+		Content: content,
+		Flags:   ephemeralFlags(ephemeral),
+	})
 }
 
 func (ctx *CommandContext) Defer(ephemeral ...bool) error {
-	return ctx.respond(types.InteractionResponseTypeDeferredChannelMessageWithSource, "", ephemeral...)
-}
-
-func (ctx *CommandContext) respond(responseType int, content string, ephemeral ...bool) error {
-	flags := 0
-	if len(ephemeral) > 0 && ephemeral[0] {
-		flags = types.MessageFlagEphemeral
-	}
-
-	response := types.InteractionResponse{
-		Type: responseType,
-		Data: &types.InteractionCallbackData{
-			Content: content,
-			Flags:   flags,
-		},
-	}
-
-	return ctx.Bot.gateway.SendInteractionResponse(ctx.Interaction.ID, ctx.Interaction.Token, response)
+	return ctx.Response.Defer(len(ephemeral) > 0 && ephemeral[0])
 }
 
 func (ctx *CommandContext) Followup(content string, ephemeral ...bool) error {
-	flags := 0
-	if len(ephemeral) > 0 && ephemeral[0] {
-		flags = types.MessageFlagEphemeral
-	}
-
-	message := types.WebhookMessage{
+	_, err := ctx.Response.Followup(types.WebhookMessage{
 		Content: content,
-		Flags:   flags,
-	}
-
-	return ctx.Bot.gateway.SendFollowupMessage(ctx.Interaction.Token, message)
+		Flags:   ephemeralFlags(ephemeral),
+	})
+	return err
 }
 
 func (ctx *CommandContext) EditResponse(content string) error {
-	return ctx.Bot.gateway.EditOriginalInteractionResponse(ctx.Interaction.Token, content)
+	_, err := ctx.Response.EditOriginal(types.WebhookMessage{Content: content})
+	return err
 }
 
-func (ctx *CommandContext) CreateModal(modal *Modal) error {
-	response := types.InteractionResponse{
-		Type: types.InteractionResponseTypeModal,
-		Data: &types.InteractionCallbackData{
-			CustomID: modal.CustomID,
-			Title:    modal.Title,
-			Components: []types.MessageComponent{
-				types.ActionRowComponent{
-					Type:       types.ComponentTypeActionRow,
-					Components: modal.Components,
-				},
-			},
-		},
+func ephemeralFlags(ephemeral []bool) int {
+	if len(ephemeral) > 0 && ephemeral[0] {
+		return types.MessageFlagEphemeral
 	}
+	return 0
+}
 
-	return ctx.Bot.gateway.SendInteractionResponse(ctx.Interaction.ID, ctx.Interaction.Token, response)
+func (ctx *CommandContext) CreateModal(modal *Modal) error {
+	response, err := modal.response()
+	if err != nil {
+		return err
+	}
+	return ctx.Response.Callback(response)
 }
 
 type Modal struct {
@@ -215,6 +248,35 @@ type Modal struct {
 	Components []types.MessageComponent
 }
 
+// response wraps each bare text input in its own action row; labels and text
+// displays are passed through as they are. The result is validated so an
+// invalid modal fails here rather than on discord.
+func (m *Modal) response() (types.InteractionResponse, error) {
+	components := make([]types.MessageComponent, len(m.Components))
+	for i, component := range m.Components {
+		if input, ok := component.(types.TextInputComponent); ok {
+			component = types.ActionRowComponent{
+				Type:       types.ComponentTypeActionRow,
+				Components: []types.MessageComponent{input},
+			}
+		}
+		components[i] = component
+	}
+
+	if err := api.ValidateModalComponents(components); err != nil {
+		return types.InteractionResponse{}, err
+	}
+
+	return types.InteractionResponse{
+		Type: types.InteractionResponseTypeModal,
+		Data: &types.InteractionCallbackData{
+			CustomID:   m.CustomID,
+			Title:      m.Title,
+			Components: components,
+		},
+	}, nil
+}
+
 func (m *Modal) AddTextInput(customID, label string, style int, options ...TextInputOption) {
 	input := types.TextInputComponent{
 		Type:     types.ComponentTypeTextInput,
@@ -230,6 +292,11 @@ func (m *Modal) AddTextInput(customID, label string, style int, options ...TextI
 	m.Components = append(m.Components, input)
 }
 
+// AddLabel adds a labelled input, see CreateLabel
+func (m *Modal) AddLabel(label, description string, component types.MessageComponent) {
+	m.Components = append(m.Components, CreateLabel(label, description, component))
+}
+
 type TextInputOption func(*types.TextInputComponent)
 
 func WithPlaceholder(placeholder string) TextInputOption {
@@ -263,6 +330,14 @@ func WithDefaultValue(value string) TextInputOption {
 }
 
 func (ih *InteractionHandler) RegisterCommand(command types.ApplicationCommand, guildID ...types.Snowflake) error {
+	ih.commandMutex.Lock()
+	command = ih.applyRoutes(command)
+	ih.commandMutex.Unlock()
+
+	if err := api.ValidateApplicationCommand(command); err != nil {
+		return err
+	}
+
 	if len(guildID) > 0 {
 		return ih.registerGuildCommand(command, guildID[0])
 	}
@@ -273,7 +348,8 @@ func (ih *InteractionHandler) registerGlobalCommand(command types.ApplicationCom
 	ih.commandMutex.Lock()
 	defer ih.commandMutex.Unlock()
 
-	if _, exists := ih.globalCommands[command.Name]; exists {
+	key := keyOf(command)
+	if _, exists := ih.globalCommands[key]; exists && !ih.placeholders[key] {
 		return fmt.Errorf("command %s already registered globally", command.Name)
 	}
 
@@ -282,7 +358,9 @@ func (ih *InteractionHandler) registerGlobalCommand(command types.ApplicationCom
 		return err
 	}
 
-	ih.globalCommands[command.Name] = command
+	// an explicit definition replaces the one UserCommand or MessageCommand added
+	delete(ih.placeholders, key)
+	ih.globalCommands[key] = command
 	return nil
 }
 
@@ -291,10 +369,10 @@ func (ih *InteractionHandler) registerGuildCommand(command types.ApplicationComm
 	defer ih.commandMutex.Unlock()
 
 	if _, exists := ih.guildCommands[guildID]; !exists {
-		ih.guildCommands[guildID] = make(map[string]types.ApplicationCommand)
+		ih.guildCommands[guildID] = make(map[commandKey]types.ApplicationCommand)
 	}
 
-	if _, exists := ih.guildCommands[guildID][command.Name]; exists {
+	if _, exists := ih.guildCommands[guildID][keyOf(command)]; exists {
 		return fmt.Errorf("command %s already registered for guild %s", command.Name, guildID)
 	}
 
@@ -303,30 +381,247 @@ func (ih *InteractionHandler) registerGuildCommand(command types.ApplicationComm
 		return err
 	}
 
-	ih.guildCommands[guildID][command.Name] = command
+	// a guild command isn't also created globally from a placeholder
+	if key := keyOf(command); ih.placeholders[key] {
+		delete(ih.placeholders, key)
+		delete(ih.globalCommands, key)
+	}
+	ih.guildCommands[guildID][keyOf(command)] = command
+	ih.syncedGuilds[guildID] = true
 	return nil
 }
 
+// UnregisterCommand forgets the commands with the given name, globally or in
+// one guild. They are deleted from discord on the next SyncCommands.
+func (ih *InteractionHandler) UnregisterCommand(name string, guildID ...types.Snowflake) {
+	ih.commandMutex.Lock()
+	defer ih.commandMutex.Unlock()
+
+	commands := ih.globalCommands
+	if len(guildID) > 0 {
+		commands = ih.guildCommands[guildID[0]]
+	}
+	for key := range commands {
+		if key.name == name {
+			delete(commands, key)
+			if len(guildID) == 0 {
+				delete(ih.placeholders, key)
+			}
+		}
+	}
+	if len(guildID) > 0 && len(commands) == 0 {
+		delete(ih.guildCommands, guildID[0])
+	}
+}
+
+// SyncCommands brings the registered commands in line with the ones known to the handler.
+// Missing commands are created, changed ones are edited and commands that no longer
+// exist in code are deleted; unchanged commands aren't touched. Guilds that had
+// commands in an earlier sync and have none left are cleared.
 func (ih *InteractionHandler) SyncCommands() error {
+	ih.commandMutex.Lock()
+	global := make([]types.ApplicationCommand, 0, len(ih.globalCommands))
 	for _, cmd := range ih.globalCommands {
-		err := ih.bot.gateway.CreateGlobalApplicationCommand(cmd)
-		if err != nil {
+		global = append(global, cmd)
+	}
+	guilds := make(map[types.Snowflake][]types.ApplicationCommand, len(ih.guildCommands))
+	for guildID, commands := range ih.guildCommands {
+		for _, cmd := range commands {
+			guilds[guildID] = append(guilds[guildID], cmd)
+		}
+	}
+	for guildID := range ih.syncedGuilds {
+		if _, exists := guilds[guildID]; !exists {
+			guilds[guildID] = nil
+		}
+	}
+	ih.commandMutex.Unlock()
+
+	if err := ih.syncScope("", global); err != nil {
+		return err
+	}
+
+	for guildID, commands := range guilds {
+		if err := ih.syncScope(guildID, commands); err != nil {
 			return err
 		}
+
+		ih.commandMutex.Lock()
+		if len(ih.guildCommands[guildID]) > 0 {
+			ih.syncedGuilds[guildID] = true
+		} else {
+			delete(ih.syncedGuilds, guildID)
+		}
+		ih.commandMutex.Unlock()
 	}
 
-	for guildID, commands := range ih.guildCommands {
-		for _, cmd := range commands {
-			err := ih.bot.gateway.CreateGuildApplicationCommand(guildID, cmd)
-			if err != nil {
-				return err
+	return nil
+}
+
+type commandKey struct {
+	name        string
+	commandType int
+}
+
+func keyOf(cmd types.ApplicationCommand) commandKey {
+	commandType := cmd.Type
+	if commandType == 0 {
+		commandType = types.ApplicationCommandTypeChatInput
+	}
+	return commandKey{name: cmd.Name, commandType: commandType}
+}
+
+func (ih *InteractionHandler) syncScope(guildID types.Snowflake, local []types.ApplicationCommand) error {
+	remote, err := ih.bot.gateway.GetApplicationCommands(guildID, true)
+	if err != nil {
+		return fmt.Errorf("failed to fetch commands: %w", err)
+	}
+
+	stale := make(map[commandKey]types.ApplicationCommand, len(remote))
+	for _, cmd := range remote {
+		stale[keyOf(cmd)] = cmd
+	}
+
+	for _, cmd := range local {
+		key := keyOf(cmd)
+		existing, ok := stale[key]
+		delete(stale, key)
+
+		switch {
+		case !ok:
+			ih.bot.logger.Infof("Creating command %s", cmd.Name)
+			if _, err := ih.bot.gateway.UpsertApplicationCommand(guildID, cmd); err != nil {
+				return fmt.Errorf("failed to create command %s: %w", cmd.Name, err)
+			}
+		case !commandsEqual(existing, cmd):
+			ih.bot.logger.Infof("Updating command %s", cmd.Name)
+			if _, err := ih.bot.gateway.EditApplicationCommand(guildID, types.Snowflake(existing.ID), cmd); err != nil {
+				return fmt.Errorf("failed to update command %s: %w", cmd.Name, err)
 			}
 		}
 	}
 
+	for _, cmd := range stale {
+		ih.bot.logger.Infof("Deleting command %s", cmd.Name)
+		if err := ih.bot.gateway.DeleteApplicationCommand(guildID, types.Snowflake(cmd.ID)); err != nil {
+			return fmt.Errorf("failed to delete command %s: %w", cmd.Name, err)
+		}
+	}
+
 	return nil
 }
 
+// commandsEqual compares the parts of a local command its author defines
+// with the command fetched from discord. Fields left unset locally match the
+// defaults discord fills in, and IDs and versions are ignored.
+func commandsEqual(remote, local types.ApplicationCommand) bool {
+	if keyOf(remote) != keyOf(local) ||
+		remote.Description != local.Description ||
+		remote.NSFW != local.NSFW ||
+		!localizationsEqual(remote.NameLocalizations, local.NameLocalizations) ||
+		!localizationsEqual(remote.DescriptionLocalizations, local.DescriptionLocalizations) {
+		return false
+	}
+
+	if (remote.DefaultMemberPermissions == nil) != (local.DefaultMemberPermissions == nil) ||
+		(local.DefaultMemberPermissions != nil && *remote.DefaultMemberPermissions != *local.DefaultMemberPermissions) {
+		return false
+	}
+
+	// contexts and integration types only apply to global commands
+	if remote.GuildID == "" {
+		if len(local.Contexts) > 0 && !intsEqual(remote.Contexts, local.Contexts) {
+			return false
+		}
+		if len(local.IntegrationTypes) > 0 && !intsEqual(remote.IntegrationTypes, local.IntegrationTypes) {
+			return false
+		}
+	}
+
+	return optionsEqual(remote.Options, local.Options)
+}
+
+func optionsEqual(remote, local []types.ApplicationCommandOption) bool {
+	if len(remote) != len(local) {
+		return false
+	}
+
+	for i := range local {
+		r, l := remote[i], local[i]
+		if r.Type != l.Type || r.Name != l.Name || r.Description != l.Description ||
+			r.Required != l.Required || r.Autocomplete != l.Autocomplete ||
+			!localizationsEqual(r.NameLocalizations, l.NameLocalizations) ||
+			!localizationsEqual(r.DescriptionLocalizations, l.DescriptionLocalizations) ||
+			!intsEqual(r.ChannelTypes, l.ChannelTypes) ||
+			!floatPtrEqual(r.MinValue, l.MinValue) || !floatPtrEqual(r.MaxValue, l.MaxValue) ||
+			!intPtrEqual(r.MinLength, l.MinLength) || !intPtrEqual(r.MaxLength, l.MaxLength) ||
+			!choicesEqual(r.Choices, l.Choices) ||
+			!optionsEqual(r.Options, l.Options) {
+			return false
+		}
+	}
+	return true
+}
+
+func choicesEqual(remote, local []types.ApplicationCommandOptionChoice) bool {
+	if len(remote) != len(local) {
+		return false
+	}
+
+	for i := range local {
+		if remote[i].Name != local[i].Name ||
+			!localizationsEqual(remote[i].NameLocalizations, local[i].NameLocalizations) ||
+			choiceValue(remote[i].Value) != choiceValue(local[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// choiceValue formats a choice value so that ints defined locally match the
+// float64 they decode to
+func choiceValue(value interface{}) string {
+	switch v := value.(type) {
+	case int:
+		return fmt.Sprint(float64(v))
+	case int64:
+		return fmt.Sprint(float64(v))
+	}
+	return fmt.Sprint(value)
+}
+
+func localizationsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for locale, value := range a {
+		if other, exists := b[locale]; !exists || other != value {
+			return false
+		}
+	}
+	return true
+}
+
+func intsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func floatPtrEqual(a, b *float64) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
+func intPtrEqual(a, b *int) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
 func CreateButton(style int, label, customID string, options ...ButtonOption) types.ButtonComponent {
 	button := types.ButtonComponent{
 		Type:     types.ComponentTypeButton,
@@ -367,6 +662,75 @@ func CreateSelectMenu(customID, placeholder string, options []types.SelectOption
 	}
 }
 
+// CreateUserSelect creates a select menu populated with the guild's users
+func CreateUserSelect(customID, placeholder string, options ...SelectMenuOption) types.SelectMenuComponent {
+	return createEntitySelect(types.ComponentTypeUserSelect, customID, placeholder, options)
+}
+
+func CreateRoleSelect(customID, placeholder string, options ...SelectMenuOption) types.SelectMenuComponent {
+	return createEntitySelect(types.ComponentTypeRoleSelect, customID, placeholder, options)
+}
+
+// CreateMentionableSelect creates a select menu with both users and roles
+func CreateMentionableSelect(customID, placeholder string, options ...SelectMenuOption) types.SelectMenuComponent {
+	return createEntitySelect(types.ComponentTypeMentionableSelect, customID, placeholder, options)
+}
+
+// CreateChannelSelect creates a channel select menu, limited to channelTypes when given
+func CreateChannelSelect(customID, placeholder string, channelTypes []int, options ...SelectMenuOption) types.SelectMenuComponent {
+	menu := createEntitySelect(types.ComponentTypeChannelSelect, customID, placeholder, options)
+	menu.ChannelTypes = channelTypes
+	return menu
+}
+
+func createEntitySelect(componentType int, customID, placeholder string, options []SelectMenuOption) types.SelectMenuComponent {
+	menu := types.SelectMenuComponent{
+		Type:        componentType,
+		CustomID:    customID,
+		Placeholder: placeholder,
+	}
+
+	for _, opt := range options {
+		opt(&menu)
+	}
+
+	return menu
+}
+
+type SelectMenuOption func(*types.SelectMenuComponent)
+
+func WithValueRange(minValues, maxValues int) SelectMenuOption {
+	return func(menu *types.SelectMenuComponent) {
+		menu.MinValues = &minValues
+		menu.MaxValues = maxValues
+	}
+}
+
+// WithDefaultValues preselects entities, see DefaultUser, DefaultRole and DefaultChannel
+func WithDefaultValues(values ...types.SelectDefaultValue) SelectMenuOption {
+	return func(menu *types.SelectMenuComponent) {
+		menu.DefaultValues = append(menu.DefaultValues, values...)
+	}
+}
+
+func WithSelectDisabled(disabled bool) SelectMenuOption {
+	return func(menu *types.SelectMenuComponent) {
+		menu.Disabled = disabled
+	}
+}
+
+func DefaultUser(id string) types.SelectDefaultValue {
+	return types.SelectDefaultValue{ID: id, Type: types.SelectDefaultValueTypeUser}
+}
+
+func DefaultRole(id string) types.SelectDefaultValue {
+	return types.SelectDefaultValue{ID: id, Type: types.SelectDefaultValueTypeRole}
+}
+
+func DefaultChannel(id string) types.SelectDefaultValue {
+	return types.SelectDefaultValue{ID: id, Type: types.SelectDefaultValueTypeChannel}
+}
+
 func CreateSelectOption(label, value, description string, defaultOption bool) types.SelectOption {
 	return types.SelectOption{
 		Label:       label,
@@ -0,0 +1,175 @@
+package bot
+
+import (
+	"github.com/nyrilol/discord-go/api"
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// Builders for the Components V2 layout components. Messages using them have
+// to be sent with types.MessageFlagIsComponentsV2, see RespondLayout.
+
+func CreateTextDisplay(content string) types.TextDisplayComponent {
+	return types.TextDisplayComponent{
+		Type:    types.ComponentTypeTextDisplay,
+		Content: content,
+	}
+}
+
+// CreateSection puts 1-3 lines of text next to an accessory, which is a
+// thumbnail or a button.
+func CreateSection(accessory types.MessageComponent, texts ...string) types.SectionComponent {
+	components := make([]types.MessageComponent, len(texts))
+	for i, text := range texts {
+		components[i] = CreateTextDisplay(text)
+	}
+
+	return types.SectionComponent{
+		Type:       types.ComponentTypeSection,
+		Components: components,
+		Accessory:  accessory,
+	}
+}
+
+func CreateThumbnail(url, description string) types.ThumbnailComponent {
+	return types.ThumbnailComponent{
+		Type:        types.ComponentTypeThumbnail,
+		Media:       types.UnfurledMediaItem{URL: url},
+		Description: description,
+	}
+}
+
+func CreateMediaGallery(items ...types.MediaGalleryItem) types.MediaGalleryComponent {
+	return types.MediaGalleryComponent{
+		Type:  types.ComponentTypeMediaGallery,
+		Items: items,
+	}
+}
+
+func CreateMediaGalleryItem(url, description string, spoiler bool) types.MediaGalleryItem {
+	return types.MediaGalleryItem{
+		Media:       types.UnfurledMediaItem{URL: url},
+		Description: description,
+		Spoiler:     spoiler,
+	}
+}
+
+// CreateFile shows an uploaded file, referenced by its filename
+func CreateFile(filename string, spoiler bool) types.FileComponent {
+	return types.FileComponent{
+		Type:    types.ComponentTypeFile,
+		File:    types.UnfurledMediaItem{URL: "attachment://" + filename},
+		Spoiler: spoiler,
+	}
+}
+
+// CreateSeparator adds spacing (types.SeparatorSpacingSmall or Large) and optionally a line
+func CreateSeparator(divider bool, spacing int) types.SeparatorComponent {
+	return types.SeparatorComponent{
+		Type:    types.ComponentTypeSeparator,
+		Divider: &divider,
+		Spacing: spacing,
+	}
+}
+
+func CreateContainer(components []types.MessageComponent, options ...ContainerOption) types.ContainerComponent {
+	container := types.ContainerComponent{
+		Type:       types.ComponentTypeContainer,
+		Components: components,
+	}
+
+	for _, opt := range options {
+		opt(&container)
+	}
+
+	return container
+}
+
+type ContainerOption func(*types.ContainerComponent)
+
+func WithAccentColor(color int) ContainerOption {
+	return func(container *types.ContainerComponent) {
+		container.AccentColor = &color
+	}
+}
+
+func WithContainerSpoiler(spoiler bool) ContainerOption {
+	return func(container *types.ContainerComponent) {
+		container.Spoiler = spoiler
+	}
+}
+
+// CreateLabel wraps a modal text input or select with a label
+func CreateLabel(label, description string, component types.MessageComponent) types.LabelComponent {
+	return types.LabelComponent{
+		Type:        types.ComponentTypeLabel,
+		Label:       label,
+		Description: description,
+		Component:   component,
+	}
+}
+
+// layoutData validates components and builds the callback data for a
+// Components V2 message.
+func layoutData(components []types.MessageComponent, ephemeral []bool) (types.InteractionCallbackData, error) {
+	if err := api.ValidateMessageComponents(components, true); err != nil {
+		return types.InteractionCallbackData{}, err
+	}
+
+	return types.InteractionCallbackData{
+		Components: components,
+		Flags:      types.MessageFlagIsComponentsV2 | ephemeralFlags(ephemeral),
+	}, nil
+}
+
+// RespondLayout responds with Components V2 components. Content and embeds
+// can't be used with them, text goes in text displays instead.
+func (ctx *CommandContext) RespondLayout(components []types.MessageComponent, ephemeral ...bool) error {
+	data, err := layoutData(components, ephemeral)
+	if err != nil {
+		return err
+	}
+	return ctx.Response.Respond(data)
+}
+
+func (ctx *ComponentContext) RespondLayout(components []types.MessageComponent, ephemeral ...bool) error {
+	data, err := layoutData(components, ephemeral)
+	if err != nil {
+		return err
+	}
+	return ctx.Response.Respond(data)
+}
+
+// UpdateLayout replaces the component's message with a Components V2 layout
+func (ctx *ComponentContext) UpdateLayout(components []types.MessageComponent) error {
+	data, err := layoutData(components, nil)
+	if err != nil {
+		return err
+	}
+	return ctx.Response.Update(data)
+}
+
+// modalInputs collects text input values from a modal submission, in both
+// the action row and the label layout.
+func modalInputs(components []types.MessageComponent) (map[string]string, map[string][]string) {
+	inputs := make(map[string]string)
+	selects := make(map[string][]string)
+	for _, component := range components {
+		var children []types.MessageComponent
+		switch c := component.(type) {
+		case types.ActionRowComponent:
+			children = c.Components
+		case types.LabelComponent:
+			children = []types.MessageComponent{c.Component}
+		}
+
+		for _, child := range children {
+			switch input := child.(type) {
+			case types.TextInputComponent:
+				inputs[input.CustomID] = input.Value
+			case types.SelectMenuComponent:
+				selects[input.CustomID] = input.Values
+			}
+		}
+	}
+	return inputs, selects
+}
@@ -0,0 +1,180 @@
+package bot
+
+import (
+	"errors"
+	"runtime/debug"
+	"strings"
+
+	"github.com/nyrilol/discord-go/api/types"
+	"github.com/nyrilol/discord-go/gateway"
+)
+
+// CommandMiddleware wraps command handlers, slash and context menu commands
+// alike. Call next to run the command (and the middlewares after this one),
+// or return without calling it to stop the command. Panics in the handler come
+// back from next as a *gateway.PanicError.
+//
+//	b.UseCommand(func(ctx *bot.CommandContext, next func() error) error {
+//		if ctx.Interaction.GuildID == "" {
+//			return ctx.Response.Respond(types.InteractionCallbackData{Content: "Servers only."})
+//		}
+//		return next()
+//	})
+type CommandMiddleware func(ctx *CommandContext, next func() error) error
+
+// Use adds an event middleware for the given events, or for every event when
+// none are given
+func (bot *Bot) Use(middleware gateway.EventMiddleware, eventNames ...string) {
+	names := make([]string, len(eventNames))
+	for i, name := range eventNames {
+		names[i] = strings.ToUpper(name)
+	}
+	bot.gateway.UseMiddleware(middleware, names...)
+}
+
+// UseCommand adds a middleware that runs around every command handler
+func (bot *Bot) UseCommand(middleware CommandMiddleware) {
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	bot.commandMiddlewares = append(bot.commandMiddlewares, middleware)
+}
+
+// SelfID returns the ID of the bot user, "" until the gateway is ready
+func (bot *Bot) SelfID() string {
+	bot.mu.RLock()
+	defer bot.mu.RUnlock()
+	return bot.selfID
+}
+
+// runCommand runs a command handler through the command middlewares and logs
+// what they returned
+func (bot *Bot) runCommand(ctx *CommandContext, handler func()) {
+	bot.mu.RLock()
+	middlewares := bot.commandMiddlewares
+	bot.mu.RUnlock()
+
+	chain := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &gateway.PanicError{Event: "command " + ctx.Command, Value: r, Stack: debug.Stack()}
+			}
+		}()
+		handler()
+		return nil
+	}
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		mw := middlewares[i]
+		next := chain
+		chain = func() error { return mw(ctx, next) }
+	}
+
+	err := chain()
+	if err == nil {
+		return
+	}
+
+	var panicErr *gateway.PanicError
+	if errors.As(err, &panicErr) {
+		bot.logger.Errorf("Command %s failed: %v\n%s", ctx.Command, err, panicErr.Stack)
+		return
+	}
+	bot.logger.Errorf("Command %s failed: %v", ctx.Command, err)
+}
+
+// Set stores a value for the middlewares and the handler after this one
+func (ctx *CommandContext) Set(key string, value interface{}) {
+	if ctx.values == nil {
+		ctx.values = make(map[string]interface{})
+	}
+	ctx.values[key] = value
+}
+
+// Get returns a value stored with Set
+func (ctx *CommandContext) Get(key string) (interface{}, bool) {
+	value, exists := ctx.values[key]
+	return value, exists
+}
+
+// eventActor holds the fields telling who caused an event
+type eventActor struct {
+	Author *types.User `json:"author"` // messages
+	User   *types.User `json:"user"`   // interactions in DMs
+	Member *struct {
+		User *types.User `json:"user"`
+	} `json:"member"` // interactions and reactions in guilds
+	UserID string `json:"user_id"` // reactions, typing
+}
+
+// actor returns the user who caused an event. Events about a user rather than
+// by one (READY, GUILD_MEMBER_ADD, ...) have no actor.
+func actor(ctx *gateway.EventContext) (id string, isBot bool, ok bool) {
+	var a eventActor
+	if err := ctx.Decode(&a); err != nil {
+		return "", false, false
+	}
+
+	switch {
+	case a.Author != nil && a.Author.ID != "":
+		return a.Author.ID, a.Author.Bot, true
+	case a.Member != nil && a.Member.User != nil:
+		return a.Member.User.ID, a.Member.User.Bot, true
+	case ctx.EventType == "INTERACTION_CREATE" && a.User != nil:
+		return a.User.ID, a.User.Bot, true
+	case a.UserID != "":
+		return a.UserID, false, true
+	}
+	return "", false, false
+}
+
+// IgnoreBots drops events caused by bots, the bot itself included
+func IgnoreBots() gateway.EventMiddleware {
+	return func(ctx *gateway.EventContext, next func() error) error {
+		if _, isBot, ok := actor(ctx); ok && isBot {
+			return nil
+		}
+		return next()
+	}
+}
+
+// IgnoreSelf drops events caused by the bot itself, such as its own messages
+func IgnoreSelf(bot *Bot) gateway.EventMiddleware {
+	return func(ctx *gateway.EventContext, next func() error) error {
+		self := bot.SelfID()
+		if id, _, ok := actor(ctx); ok && self != "" && id == self {
+			return nil
+		}
+		return next()
+	}
+}
+
+// AllowGuilds only lets events from the given guilds through. Events outside
+// guilds (DMs, READY, ...) are not affected.
+func AllowGuilds(guildIDs ...string) gateway.EventMiddleware {
+	allowed := guildSet(guildIDs)
+	return func(ctx *gateway.EventContext, next func() error) error {
+		if guildID := ctx.GuildID(); guildID != "" && !allowed[guildID] {
+			return nil
+		}
+		return next()
+	}
+}
+
+// DenyGuilds drops events from the given guilds
+func DenyGuilds(guildIDs ...string) gateway.EventMiddleware {
+	denied := guildSet(guildIDs)
+	return func(ctx *gateway.EventContext, next func() error) error {
+		if denied[ctx.GuildID()] {
+			return nil
+		}
+		return next()
+	}
+}
+
+func guildSet(guildIDs []string) map[string]bool {
+	set := make(map[string]bool, len(guildIDs))
+	for _, id := range guildIDs {
+		set[id] = true
+	}
+	return set
+}
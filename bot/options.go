@@ -0,0 +1,241 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+var optionTypeNames = map[int]string{
+	types.ApplicationCommandOptionTypeSubCommand:      "subcommand",
+	types.ApplicationCommandOptionTypeSubCommandGroup: "subcommand group",
+	types.ApplicationCommandOptionTypeString:          "string",
+	types.ApplicationCommandOptionTypeInteger:         "integer",
+	types.ApplicationCommandOptionTypeBoolean:         "boolean",
+	types.ApplicationCommandOptionTypeUser:            "user",
+	types.ApplicationCommandOptionTypeChannel:         "channel",
+	types.ApplicationCommandOptionTypeRole:            "role",
+	types.ApplicationCommandOptionTypeMentionable:     "mentionable",
+	types.ApplicationCommandOptionTypeNumber:          "number",
+	types.ApplicationCommandOptionTypeAttachment:      "attachment",
+}
+
+// OptionTypeError is reported when an option is read with the wrong accessor,
+// e.g. ctx.Int on a string option.
+type OptionTypeError struct {
+	Option   string
+	Expected string
+	Actual   int
+}
+
+func (e *OptionTypeError) Error() string {
+	actual, ok := optionTypeNames[e.Actual]
+	if !ok {
+		actual = fmt.Sprintf("type %d", e.Actual)
+	}
+	return fmt.Sprintf("option %q is a %s, not a %s", e.Option, actual, e.Expected)
+}
+
+// Mentionable is the value of a mentionable option, either a user (with the
+// member when used in a guild) or a role.
+type Mentionable struct {
+	User   *types.User
+	Member *types.GuildMember
+	Role   *types.Role
+}
+
+func newCommandContext(interaction *types.Interaction, bot *Bot, path string, options []types.ApplicationCommandInteractionOption) *CommandContext {
+	ctx := &CommandContext{
+		Interaction: interaction,
+		Bot:         bot,
+		Command:     path,
+		Options:     make(map[string]interface{}, len(options)),
+		Response:    newInteractionResponder(bot, interaction),
+		options:     make(map[string]types.ApplicationCommandInteractionOption, len(options)),
+	}
+
+	for _, option := range options {
+		ctx.Options[option.Name] = option.Value
+		ctx.options[option.Name] = option
+	}
+
+	return ctx
+}
+
+// Err returns the first type mismatch reported by an option accessor
+func (ctx *CommandContext) Err() error {
+	return ctx.optionErr
+}
+
+// option looks up an option and checks its type. ok is false when the option
+// wasn't given or has another type; the latter is also recorded for Err.
+func (ctx *CommandContext) option(name, expected string, optionTypes ...int) (types.ApplicationCommandInteractionOption, bool) {
+	option, exists := ctx.options[name]
+	if !exists {
+		return option, false
+	}
+
+	for _, optionType := range optionTypes {
+		if option.Type == optionType {
+			return option, true
+		}
+	}
+
+	err := &OptionTypeError{Option: name, Expected: expected, Actual: option.Type}
+	if ctx.optionErr == nil {
+		ctx.optionErr = err
+	}
+	if ctx.Bot != nil && ctx.Bot.logger != nil {
+		ctx.Bot.logger.Warnf("Command %s: %v", ctx.Command, err)
+	}
+	return option, false
+}
+
+func (ctx *CommandContext) resolved() *types.ResolvedData {
+	if ctx.Interaction == nil || ctx.Interaction.Data.Resolved == nil {
+		return &types.ResolvedData{}
+	}
+	return ctx.Interaction.Data.Resolved
+}
+
+func (ctx *CommandContext) String(name string) (string, bool) {
+	option, ok := ctx.option(name, "string", types.ApplicationCommandOptionTypeString)
+	if !ok {
+		return "", false
+	}
+	value, ok := option.Value.(string)
+	return value, ok
+}
+
+func (ctx *CommandContext) Int(name string) (int64, bool) {
+	option, ok := ctx.option(name, "integer", types.ApplicationCommandOptionTypeInteger)
+	if !ok {
+		return 0, false
+	}
+	value, ok := option.Value.(float64)
+	return int64(value), ok
+}
+
+// Float reads a number option. Integer options are accepted too.
+func (ctx *CommandContext) Float(name string) (float64, bool) {
+	option, ok := ctx.option(name, "number", types.ApplicationCommandOptionTypeNumber, types.ApplicationCommandOptionTypeInteger)
+	if !ok {
+		return 0, false
+	}
+	value, ok := option.Value.(float64)
+	return value, ok
+}
+
+func (ctx *CommandContext) Bool(name string) (bool, bool) {
+	option, ok := ctx.option(name, "boolean", types.ApplicationCommandOptionTypeBoolean)
+	if !ok {
+		return false, false
+	}
+	value, ok := option.Value.(bool)
+	return value, ok
+}
+
+// Snowflake returns the raw ID of a user, channel, role, mentionable or attachment option
+func (ctx *CommandContext) Snowflake(name string) (types.Snowflake, bool) {
+	option, ok := ctx.option(name, "snowflake",
+		types.ApplicationCommandOptionTypeUser,
+		types.ApplicationCommandOptionTypeChannel,
+		types.ApplicationCommandOptionTypeRole,
+		types.ApplicationCommandOptionTypeMentionable,
+		types.ApplicationCommandOptionTypeAttachment,
+	)
+	if !ok {
+		return "", false
+	}
+	value, ok := option.Value.(string)
+	return types.Snowflake(value), ok
+}
+
+func (ctx *CommandContext) User(name string) (*types.User, bool) {
+	option, ok := ctx.option(name, "user", types.ApplicationCommandOptionTypeUser, types.ApplicationCommandOptionTypeMentionable)
+	if !ok {
+		return nil, false
+	}
+	user, exists := ctx.resolved().Users[fmt.Sprint(option.Value)]
+	if !exists {
+		return nil, false
+	}
+	return &user, true
+}
+
+// Member returns the guild member of a user option. Resolved members don't
+// include the user, so it's filled in from the resolved users.
+func (ctx *CommandContext) Member(name string) (*types.GuildMember, bool) {
+	option, ok := ctx.option(name, "user", types.ApplicationCommandOptionTypeUser, types.ApplicationCommandOptionTypeMentionable)
+	if !ok {
+		return nil, false
+	}
+	return resolveMember(ctx.resolved(), fmt.Sprint(option.Value))
+}
+
+func (ctx *CommandContext) Role(name string) (*types.Role, bool) {
+	option, ok := ctx.option(name, "role", types.ApplicationCommandOptionTypeRole, types.ApplicationCommandOptionTypeMentionable)
+	if !ok {
+		return nil, false
+	}
+	role, exists := ctx.resolved().Roles[fmt.Sprint(option.Value)]
+	if !exists {
+		return nil, false
+	}
+	return &role, true
+}
+
+func (ctx *CommandContext) Channel(name string) (*types.Channel, bool) {
+	option, ok := ctx.option(name, "channel", types.ApplicationCommandOptionTypeChannel)
+	if !ok {
+		return nil, false
+	}
+	channel, exists := ctx.resolved().Channels[fmt.Sprint(option.Value)]
+	if !exists {
+		return nil, false
+	}
+	return &channel, true
+}
+
+func (ctx *CommandContext) Attachment(name string) (*types.Attachment, bool) {
+	option, ok := ctx.option(name, "attachment", types.ApplicationCommandOptionTypeAttachment)
+	if !ok {
+		return nil, false
+	}
+	attachment, exists := ctx.resolved().Attachments[fmt.Sprint(option.Value)]
+	if !exists {
+		return nil, false
+	}
+	return &attachment, true
+}
+
+func (ctx *CommandContext) Mentionable(name string) (*Mentionable, bool) {
+	option, ok := ctx.option(name, "mentionable", types.ApplicationCommandOptionTypeMentionable, types.ApplicationCommandOptionTypeUser, types.ApplicationCommandOptionTypeRole)
+	if !ok {
+		return nil, false
+	}
+	return resolveMentionable(ctx.resolved(), fmt.Sprint(option.Value))
+}
+
+func resolveMember(resolved *types.ResolvedData, id string) (*types.GuildMember, bool) {
+	member, exists := resolved.Members[id]
+	if !exists {
+		return nil, false
+	}
+	if user, exists := resolved.Users[id]; exists {
+		member.User = user
+	}
+	return &member, true
+}
+
+func resolveMentionable(resolved *types.ResolvedData, id string) (*Mentionable, bool) {
+	if user, exists := resolved.Users[id]; exists {
+		mentionable := &Mentionable{User: &user}
+		mentionable.Member, _ = resolveMember(resolved, id)
+		return mentionable, true
+	}
+	if role, exists := resolved.Roles[id]; exists {
+		return &Mentionable{Role: &role}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,307 @@
+package bot
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// DefaultPaginatorTimeout is how long a paginator stays usable after the last click
+const DefaultPaginatorTimeout = 5 * time.Minute
+
+// paginatorPrefix starts the custom ID of every paginator button and modal
+const paginatorPrefix = "paginator"
+
+// Page is one page of a Paginator
+type Page struct {
+	Content string
+	Embeds  []*types.Embed
+}
+
+// EmbedPages makes one page per embed
+func EmbedPages(embeds ...*types.Embed) []Page {
+	pages := make([]Page, len(embeds))
+	for i, embed := range embeds {
+		pages[i] = Page{Embeds: []*types.Embed{embed}}
+	}
+	return pages
+}
+
+// Paginator sends pages with first/previous/next/last buttons and a jump
+// button showing the current page, which asks for a page number. Only the
+// user who ran the command can use the buttons, and they are disabled once
+// Timeout passes without a click.
+//
+//	p := bot.NewPaginator(ih, bot.EmbedPages(embeds...)...)
+//	return p.Send(ctx)
+type Paginator struct {
+	Pages     []Page
+	Timeout   time.Duration // zero keeps the paginator until Stop is called
+	Ephemeral bool
+
+	ih      *InteractionHandler
+	id      string
+	ownerID string
+
+	// response is the latest interaction that showed the paginator; its token
+	// is the freshest for editing the message. channelID and messageID are
+	// known after the first click and used once the token has expired.
+	response  *InteractionResponder
+	channelID string
+	messageID string
+
+	mu      sync.Mutex
+	index   int
+	timer   *time.Timer
+	stopped bool
+}
+
+func NewPaginator(ih *InteractionHandler, pages ...Page) *Paginator {
+	ih.paginatorOnce.Do(func() {
+		ih.Button(paginatorPrefix+":{id}:{action}", ih.handlePaginatorButton)
+		ih.Modal(paginatorPrefix+":{id}:jump", ih.handlePaginatorJump)
+	})
+
+	return &Paginator{
+		Pages:   pages,
+		Timeout: DefaultPaginatorTimeout,
+		ih:      ih,
+		id:      newPaginatorID(),
+	}
+}
+
+func newPaginatorID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Send responds to the command with the first page and starts listening for clicks
+func (p *Paginator) Send(ctx *CommandContext) error {
+	if len(p.Pages) == 0 {
+		return fmt.Errorf("paginator has no pages")
+	}
+
+	p.mu.Lock()
+	p.ownerID = interactionUserID(ctx.Interaction)
+	p.response = ctx.Response
+	data := p.pageDataLocked(false)
+	if p.Ephemeral {
+		data.Flags |= types.MessageFlagEphemeral
+	}
+	p.mu.Unlock()
+
+	if err := ctx.Response.Respond(data); err != nil {
+		return err
+	}
+
+	p.ih.componentMutex.Lock()
+	p.ih.paginators[p.id] = p
+	p.ih.componentMutex.Unlock()
+
+	p.mu.Lock()
+	p.resetTimerLocked()
+	p.mu.Unlock()
+	return nil
+}
+
+// Stop disables the buttons and forgets the paginator
+func (p *Paginator) Stop() {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return
+	}
+	p.stopped = true
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	data := p.pageDataLocked(true)
+	response, channelID, messageID := p.response, p.channelID, p.messageID
+	p.mu.Unlock()
+
+	p.ih.componentMutex.Lock()
+	delete(p.ih.paginators, p.id)
+	p.ih.componentMutex.Unlock()
+
+	message := types.WebhookMessage{Components: data.Components}
+	var err error
+	switch {
+	case response != nil && !response.Expired():
+		_, err = response.EditOriginal(message)
+	case messageID != "" && !p.Ephemeral:
+		// ephemeral messages can only be edited through an interaction token
+		_, err = p.ih.bot.gateway.EditMessage(channelID, messageID, message)
+	default:
+		return
+	}
+	if err != nil {
+		p.ih.bot.logger.Warnf("Failed to disable paginator buttons: %v", err)
+	}
+}
+
+func (p *Paginator) resetTimerLocked() {
+	if p.Timeout <= 0 {
+		return
+	}
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	p.timer = time.AfterFunc(p.Timeout, p.Stop)
+}
+
+// pageDataLocked renders the current page with its buttons
+func (p *Paginator) pageDataLocked(disabled bool) types.InteractionCallbackData {
+	page := p.Pages[p.index]
+	last := len(p.Pages) - 1
+
+	button := func(action, label string, off bool) types.MessageComponent {
+		return CreateButton(types.ButtonStyleSecondary, label, p.customID(action), WithDisabled(disabled || off))
+	}
+
+	return types.InteractionCallbackData{
+		Content: page.Content,
+		Embeds:  page.Embeds,
+		Components: []types.MessageComponent{
+			types.ActionRowComponent{
+				Type: types.ComponentTypeActionRow,
+				Components: []types.MessageComponent{
+					button("first", "«", p.index == 0),
+					button("prev", "‹", p.index == 0),
+					button("jump", fmt.Sprintf("%d/%d", p.index+1, len(p.Pages)), last == 0),
+					button("next", "›", p.index == last),
+					button("last", "»", p.index == last),
+				},
+			},
+		},
+	}
+}
+
+func (p *Paginator) customID(action string) string {
+	return paginatorPrefix + ":" + p.id + ":" + action
+}
+
+func (ih *InteractionHandler) lookupPaginator(id string) (*Paginator, bool) {
+	ih.componentMutex.Lock()
+	defer ih.componentMutex.Unlock()
+	p, exists := ih.paginators[id]
+	return p, exists
+}
+
+// checkPaginator finds the paginator of a click and makes sure the clicking
+// user is the one who ran the command, answering the interaction otherwise.
+func checkPaginator(ih *InteractionHandler, id string, interaction *types.Interaction, response *InteractionResponder) (*Paginator, bool) {
+	p, exists := ih.lookupPaginator(id)
+	if !exists {
+		response.Respond(types.InteractionCallbackData{
+			Content: "This menu has expired.",
+			Flags:   types.MessageFlagEphemeral,
+		})
+		return nil, false
+	}
+
+	if p.ownerID != "" && interactionUserID(interaction) != p.ownerID {
+		response.Respond(types.InteractionCallbackData{
+			Content: "Only the person who ran the command can use these buttons.",
+			Flags:   types.MessageFlagEphemeral,
+		})
+		return nil, false
+	}
+
+	return p, true
+}
+
+func (ih *InteractionHandler) handlePaginatorButton(ctx *ComponentContext) {
+	p, ok := checkPaginator(ih, ctx.Param("id"), ctx.Interaction, ctx.Response)
+	if !ok {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch ctx.Param("action") {
+	case "first":
+		p.index = 0
+	case "prev":
+		if p.index > 0 {
+			p.index--
+		}
+	case "next":
+		if p.index < len(p.Pages)-1 {
+			p.index++
+		}
+	case "last":
+		p.index = len(p.Pages) - 1
+	case "jump":
+		modal := &Modal{CustomID: p.customID("jump"), Title: "Go to page"}
+		modal.AddTextInput("page", fmt.Sprintf("Page (1-%d)", len(p.Pages)), types.TextInputStyleShort,
+			WithPlaceholder(strconv.Itoa(p.index+1)), WithRequired(true), WithMaxLength(len(strconv.Itoa(len(p.Pages)))))
+		if err := ctx.ShowModal(modal); err != nil {
+			ih.bot.logger.Errorf("Failed to show paginator modal: %v", err)
+		}
+		p.resetTimerLocked()
+		return
+	}
+
+	p.updateLocked(ctx.Response)
+}
+
+func (ih *InteractionHandler) handlePaginatorJump(ctx *ModalContext) {
+	p, ok := checkPaginator(ih, ctx.Param("id"), ctx.Interaction, ctx.Response)
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(strings.TrimSpace(ctx.Inputs["page"]))
+	if err != nil || page < 1 || page > len(p.Pages) {
+		ctx.Respond(fmt.Sprintf("Pick a page between 1 and %d.", len(p.Pages)), true)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.index = page - 1
+	p.updateLocked(ctx.Response)
+}
+
+func (p *Paginator) updateLocked(response *InteractionResponder) {
+	if p.stopped {
+		// stopped between the lookup and now; still answer the click
+		response.Respond(types.InteractionCallbackData{
+			Content: "This menu has expired.",
+			Flags:   types.MessageFlagEphemeral,
+		})
+		return
+	}
+	if err := response.Update(p.pageDataLocked(false)); err != nil {
+		p.ih.bot.logger.Errorf("Failed to update paginator: %v", err)
+		return
+	}
+
+	p.response = response
+	if message := response.interaction.Message; message != nil {
+		p.channelID, p.messageID = message.ChannelID, message.ID
+	}
+	p.resetTimerLocked()
+}
+
+// interactionUserID returns the ID of the user who triggered an interaction,
+// in guilds and in DMs.
+func interactionUserID(interaction *types.Interaction) string {
+	if interaction.Member != nil {
+		return interaction.Member.User.ID
+	}
+	if interaction.User != nil {
+		return interaction.User.ID
+	}
+	return ""
+}
@@ -0,0 +1,308 @@
+package bot
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nyrilol/discord-go/api"
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+const (
+	// InteractionTokenLifetime is how long followups and edits are possible
+	InteractionTokenLifetime = 15 * time.Minute
+
+	discordEpoch = 1420070400000
+)
+
+var ErrInteractionExpired = errors.New("interaction token has expired")
+
+type responseState int
+
+const (
+	responseNone responseState = iota
+	responseDeferred
+	responseSent
+)
+
+// InteractionResponder tracks whether an interaction has been acknowledged and
+// picks the right endpoint for each response: the callback for the first one,
+// an edit of the original after a defer, and followups after that.
+type InteractionResponder struct {
+	bot         *Bot
+	interaction *types.Interaction
+	webhook     *api.WebhookClient
+	expiresAt   time.Time
+
+	mu             sync.Mutex
+	state          responseState
+	deferType      int // type of the defer when state is responseDeferred
+	originalEdited bool
+	autoDefer      *time.Timer
+}
+
+func newInteractionResponder(bot *Bot, interaction *types.Interaction) *InteractionResponder {
+	// interactions received over HTTP share the responder the server created
+	// before dispatching, so a deferred HTTP answer is tracked as an acknowledgement
+	if value, ok := bot.pending.Load(interaction.ID); ok {
+		return value.(*pendingResponse).responder
+	}
+
+	return &InteractionResponder{
+		bot:         bot,
+		interaction: interaction,
+		webhook:     api.NewWebhookClient(interaction.ApplicationID.String(), interaction.Token),
+		expiresAt:   snowflakeTime(interaction.ID).Add(InteractionTokenLifetime),
+	}
+}
+
+// snowflakeTime returns when a snowflake was created, or now if it can't be parsed
+func snowflakeTime(id types.Snowflake) time.Time {
+	n, err := strconv.ParseInt(id.String(), 10, 64)
+	if err != nil || n <= 0 {
+		return time.Now()
+	}
+	return time.UnixMilli((n >> 22) + discordEpoch)
+}
+
+// Acknowledged reports whether the initial response (or a defer) was sent
+func (r *InteractionResponder) Acknowledged() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state != responseNone
+}
+
+// answered reports whether a message was sent, as the initial response or as
+// the edit of a deferred one
+func (r *InteractionResponder) answered() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state == responseSent || r.originalEdited
+}
+
+// Expired reports whether the 15 minute interaction token has run out
+func (r *InteractionResponder) Expired() bool {
+	return time.Now().After(r.expiresAt)
+}
+
+// AutoDefer defers the interaction if nothing was sent after the given delay.
+// deferType is InteractionResponseTypeDeferredChannelMessageWithSource or
+// InteractionResponseTypeDeferredUpdateMessage.
+func (r *InteractionResponder) AutoDefer(after time.Duration, deferType int, ephemeral bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state != responseNone || after <= 0 {
+		return
+	}
+	if r.autoDefer != nil {
+		r.autoDefer.Stop()
+	}
+
+	r.autoDefer = time.AfterFunc(after, func() {
+		if err := r.deferWith(deferType, ephemeral); err != nil && !errors.Is(err, ErrAlreadyResponded) {
+			r.bot.logger.Errorf("Failed to auto-defer interaction %s: %v", r.interaction.ID, err)
+		}
+	})
+}
+
+// Callback sends the initial response. It fails if the interaction was already acknowledged.
+func (r *InteractionResponder) Callback(response types.InteractionResponse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.callbackLocked(response)
+}
+
+func (r *InteractionResponder) callbackLocked(response types.InteractionResponse) error {
+	if r.state != responseNone {
+		return ErrAlreadyResponded
+	}
+
+	if err := r.bot.SendInteractionResponse(r.interaction.ID, r.interaction.Token, response); err != nil {
+		return err
+	}
+
+	switch response.Type {
+	case types.InteractionResponseTypeDeferredChannelMessageWithSource, types.InteractionResponseTypeDeferredUpdateMessage:
+		r.setStateLocked(responseDeferred)
+		r.deferType = response.Type
+	default:
+		r.setStateLocked(responseSent)
+	}
+	return nil
+}
+
+func (r *InteractionResponder) setStateLocked(state responseState) {
+	r.state = state
+	if r.autoDefer != nil {
+		r.autoDefer.Stop()
+		r.autoDefer = nil
+	}
+}
+
+// markDeferred records a defer that was sent outside of the responder
+func (r *InteractionResponder) markDeferred(deferType int) {
+	if r.state == responseNone {
+		r.setStateLocked(responseDeferred)
+		r.deferType = deferType
+	}
+}
+
+// Respond sends a message as the initial response, as an edit of the deferred
+// original, or as a followup, depending on what was sent before. After a
+// deferred update the original is the component's message, so a followup is sent.
+func (r *InteractionResponder) Respond(data types.InteractionCallbackData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case r.state == responseNone:
+		return r.callbackLocked(types.InteractionResponse{
+			Type: types.InteractionResponseTypeChannelMessageWithSource,
+			Data: &data,
+		})
+	case r.state == responseDeferred && r.deferType == types.InteractionResponseTypeDeferredChannelMessageWithSource && !r.originalEdited:
+		if _, err := r.editMessage("@original", callbackToWebhookMessage(data)); err != nil {
+			return err
+		}
+		r.originalEdited = true
+		return nil
+	default:
+		_, err := r.followup(callbackToWebhookMessage(data))
+		return err
+	}
+}
+
+// Defer acknowledges the interaction with a "thinking..." state
+func (r *InteractionResponder) Defer(ephemeral bool) error {
+	return r.deferWith(types.InteractionResponseTypeDeferredChannelMessageWithSource, ephemeral)
+}
+
+// Update edits the message a component is attached to. It's sent as the initial
+// response when nothing was sent yet, otherwise the original is edited.
+func (r *InteractionResponder) Update(data types.InteractionCallbackData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state == responseNone {
+		return r.callbackLocked(types.InteractionResponse{
+			Type: types.InteractionResponseTypeUpdateMessage,
+			Data: &data,
+		})
+	}
+
+	if _, err := r.editMessage("@original", callbackToWebhookMessage(data)); err != nil {
+		return err
+	}
+	r.originalEdited = true
+	return nil
+}
+
+// DeferUpdate acknowledges a component interaction without a loading state,
+// leaving the message as it is until Update is called.
+func (r *InteractionResponder) DeferUpdate() error {
+	return r.deferWith(types.InteractionResponseTypeDeferredUpdateMessage, false)
+}
+
+func (r *InteractionResponder) deferWith(deferType int, ephemeral bool) error {
+	response := types.InteractionResponse{Type: deferType}
+	if ephemeral && deferType == types.InteractionResponseTypeDeferredChannelMessageWithSource {
+		response.Data = &types.InteractionCallbackData{Flags: types.MessageFlagEphemeral}
+	}
+	return r.Callback(response)
+}
+
+func (r *InteractionResponder) Followup(message types.WebhookMessage) (*types.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state == responseNone {
+		return nil, errors.New("interaction must be acknowledged before sending followups")
+	}
+	return r.followup(message)
+}
+
+func (r *InteractionResponder) followup(message types.WebhookMessage) (*types.Message, error) {
+	if r.Expired() {
+		return nil, ErrInteractionExpired
+	}
+	return r.webhook.Execute(message)
+}
+
+func (r *InteractionResponder) GetOriginal() (*types.Message, error) {
+	if r.Expired() {
+		return nil, ErrInteractionExpired
+	}
+	return r.webhook.GetMessage("@original")
+}
+
+func (r *InteractionResponder) EditOriginal(message types.WebhookMessage) (*types.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	edited, err := r.editMessage("@original", message)
+	if err == nil {
+		r.originalEdited = true
+	}
+	return edited, err
+}
+
+func (r *InteractionResponder) DeleteOriginal() error {
+	return r.deleteMessage("@original")
+}
+
+func (r *InteractionResponder) EditFollowup(messageID string, message types.WebhookMessage) (*types.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.editMessage(messageID, message)
+}
+
+func (r *InteractionResponder) DeleteFollowup(messageID string) error {
+	return r.deleteMessage(messageID)
+}
+
+func (r *InteractionResponder) editMessage(messageID string, message types.WebhookMessage) (*types.Message, error) {
+	if r.Expired() {
+		return nil, ErrInteractionExpired
+	}
+	return r.webhook.EditMessage(messageID, message)
+}
+
+func (r *InteractionResponder) deleteMessage(messageID string) error {
+	if r.Expired() {
+		return ErrInteractionExpired
+	}
+	return r.webhook.DeleteMessage(messageID)
+}
+
+func callbackToWebhookMessage(data types.InteractionCallbackData) types.WebhookMessage {
+	return types.WebhookMessage{
+		Content:         data.Content,
+		TTS:             data.TTS,
+		Embeds:          data.Embeds,
+		AllowedMentions: data.AllowedMentions,
+		Components:      data.Components,
+		Attachments:     data.Attachments,
+		Flags:           data.Flags,
+	}
+}
+
+// SetAutoDefer makes handlers defer automatically when they haven't responded
+// within the given time. Zero turns it off. Discord gives up after 3 seconds,
+// so anything between 1.5 and 2.5 seconds works well.
+func (ih *InteractionHandler) SetAutoDefer(after time.Duration, ephemeral bool) {
+	ih.commandMutex.Lock()
+	defer ih.commandMutex.Unlock()
+	ih.autoDefer = after
+	ih.autoDeferEphemeral = ephemeral
+}
+
+func (ih *InteractionHandler) startAutoDefer(r *InteractionResponder, deferType int) {
+	ih.commandMutex.Lock()
+	after, ephemeral := ih.autoDefer, ih.autoDeferEphemeral
+	ih.commandMutex.Unlock()
+
+	r.AutoDefer(after, deferType, ephemeral)
+}
@@ -0,0 +1,453 @@
+package bot
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// TypedCommand registers a command whose options are described by the fields
+// of T and whose handler gets them decoded into a T:
+//
+//	type BanArgs struct {
+//		User   *types.User `discord:"user,required" description:"Who to ban"`
+//		Reason string      `discord:"reason,max=512" description:"Why"`
+//		Days   *int64      `discord:"days,min=0,max=7"`
+//	}
+//
+//	cmd, err := bot.TypedCommand(ih, "ban", "Ban a user", func(ctx *bot.CommandContext, args BanArgs) { ... })
+//
+// The discord tag holds the option name followed by flags: required, autocomplete,
+// min=, max= (value for numbers, length for strings), choices=a|b|Label:value
+// and channel_types=0|5. Pointer fields are nil when the option wasn't given.
+// Integer fields smaller than int64 get min and max defaults from their type.
+//
+// name can be a subcommand path, in which case the options are added to the
+// parent command definition. The returned command is the generated definition
+// for top-level names and should be passed to RegisterCommand.
+func TypedCommand[T any](ih *InteractionHandler, name, description string, handler func(*CommandContext, T)) (types.ApplicationCommand, error) {
+	spec, err := structSpecFor(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return types.ApplicationCommand{}, err
+	}
+
+	wrapped := func(ctx *CommandContext) {
+		var args T
+		if err := spec.decode(ctx, reflect.ValueOf(&args).Elem()); err != nil {
+			ih.bot.logger.Errorf("Failed to decode options for %s: %v", ctx.Command, err)
+			// Respond edits the original instead when the interaction was deferred
+			if err := ctx.Respond("Couldn't read the command's options: "+err.Error(), true); err != nil {
+				ih.bot.logger.Errorf("Failed to answer command %s: %v", ctx.Command, err)
+			}
+			return
+		}
+		handler(ctx, args)
+	}
+
+	options := spec.options()
+	path := normalizeCommandPath(name)
+	if strings.Contains(path, " ") {
+		err := ih.Command(path, wrapped, WithDescription(description), WithOptions(options...))
+		return types.ApplicationCommand{}, err
+	}
+
+	if err := ih.Command(path, wrapped); err != nil {
+		return types.ApplicationCommand{}, err
+	}
+	return types.ApplicationCommand{
+		Type:        types.ApplicationCommandTypeChatInput,
+		Name:        path,
+		Description: description,
+		Options:     options,
+	}, nil
+}
+
+type fieldSpec struct {
+	index  int
+	option types.ApplicationCommandOption
+	kind   reflect.Type // field type with the pointer stripped
+	ptr    bool
+}
+
+type structSpec struct {
+	fields []fieldSpec
+}
+
+var (
+	userType        = reflect.TypeOf(types.User{})
+	memberType      = reflect.TypeOf(types.GuildMember{})
+	roleType        = reflect.TypeOf(types.Role{})
+	channelType     = reflect.TypeOf(types.Channel{})
+	attachmentType  = reflect.TypeOf(types.Attachment{})
+	mentionableType = reflect.TypeOf(Mentionable{})
+	snowflakeType   = reflect.TypeOf(types.Snowflake(""))
+)
+
+func structSpecFor(t reflect.Type) (*structSpec, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("command arguments must be a struct, got %s", t)
+	}
+
+	spec := &structSpec{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("discord")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+
+		fs, err := parseField(field, tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		fs.index = i
+		spec.fields = append(spec.fields, fs)
+	}
+
+	// discord wants required options first
+	sort.SliceStable(spec.fields, func(i, j int) bool {
+		return spec.fields[i].option.Required && !spec.fields[j].option.Required
+	})
+
+	return spec, nil
+}
+
+func parseField(field reflect.StructField, tag string) (fieldSpec, error) {
+	fs := fieldSpec{kind: field.Type}
+	if fs.kind.Kind() == reflect.Ptr {
+		fs.kind = fs.kind.Elem()
+		fs.ptr = true
+	}
+
+	optionType, err := optionTypeFor(fs.kind)
+	if err != nil {
+		return fs, err
+	}
+
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = snakeCase(field.Name)
+	}
+
+	fs.option = types.ApplicationCommandOption{
+		Type:        optionType,
+		Name:        name,
+		Description: field.Tag.Get("description"),
+	}
+	if fs.option.Description == "" {
+		fs.option.Description = name
+	}
+
+	for _, flag := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(flag), "=")
+		switch key {
+		case "":
+		case "required":
+			fs.option.Required = true
+		case "autocomplete":
+			fs.option.Autocomplete = true
+		case "min", "max":
+			if err := applyBound(&fs.option, key, value); err != nil {
+				return fs, err
+			}
+		case "choices":
+			choices, err := parseChoices(optionType, value)
+			if err != nil {
+				return fs, err
+			}
+			fs.option.Choices = choices
+		case "channel_types":
+			for _, raw := range strings.Split(value, "|") {
+				channelType, err := strconv.Atoi(raw)
+				if err != nil {
+					return fs, fmt.Errorf("invalid channel type %q", raw)
+				}
+				fs.option.ChannelTypes = append(fs.option.ChannelTypes, channelType)
+			}
+		default:
+			return fs, fmt.Errorf("unknown tag flag %q", key)
+		}
+	}
+
+	if optionType == types.ApplicationCommandOptionTypeInteger {
+		applyKindBounds(&fs.option, fs.kind)
+	}
+	return fs, nil
+}
+
+// applyKindBounds limits an integer option to what its field can hold, so
+// discord rejects values that would otherwise wrap around. Bounds set in the
+// tag are kept.
+func applyKindBounds(option *types.ApplicationCommandOption, kind reflect.Type) {
+	var min, max float64
+	switch kind.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		min, max = -math.Ldexp(1, kind.Bits()-1), math.Ldexp(1, kind.Bits()-1)-1
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		min, max = 0, math.Ldexp(1, kind.Bits())-1
+	case reflect.Uint, reflect.Uint64:
+		if option.MinValue == nil {
+			option.MinValue = &min
+		}
+		return
+	default:
+		return
+	}
+
+	if option.MinValue == nil {
+		option.MinValue = &min
+	}
+	if option.MaxValue == nil {
+		option.MaxValue = &max
+	}
+}
+
+func optionTypeFor(t reflect.Type) (int, error) {
+	switch t {
+	case userType, memberType:
+		return types.ApplicationCommandOptionTypeUser, nil
+	case roleType:
+		return types.ApplicationCommandOptionTypeRole, nil
+	case channelType:
+		return types.ApplicationCommandOptionTypeChannel, nil
+	case attachmentType:
+		return types.ApplicationCommandOptionTypeAttachment, nil
+	case mentionableType:
+		return types.ApplicationCommandOptionTypeMentionable, nil
+	case snowflakeType:
+		return 0, fmt.Errorf("use a resolved type (types.User, types.Role, ...) instead of types.Snowflake")
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return types.ApplicationCommandOptionTypeString, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return types.ApplicationCommandOptionTypeInteger, nil
+	case reflect.Float32, reflect.Float64:
+		return types.ApplicationCommandOptionTypeNumber, nil
+	case reflect.Bool:
+		return types.ApplicationCommandOptionTypeBoolean, nil
+	}
+
+	return 0, fmt.Errorf("unsupported option type %s", t)
+}
+
+// applyBound sets min/max as a length for strings and as a value for numbers
+func applyBound(option *types.ApplicationCommandOption, key, value string) error {
+	switch option.Type {
+	case types.ApplicationCommandOptionTypeString:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s length %q", key, value)
+		}
+		if key == "min" {
+			option.MinLength = &n
+		} else {
+			option.MaxLength = &n
+		}
+	case types.ApplicationCommandOptionTypeInteger, types.ApplicationCommandOptionTypeNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s value %q", key, value)
+		}
+		if key == "min" {
+			option.MinValue = &n
+		} else {
+			option.MaxValue = &n
+		}
+	default:
+		return fmt.Errorf("%s is only supported for string, integer and number options", key)
+	}
+	return nil
+}
+
+func parseChoices(optionType int, raw string) ([]types.ApplicationCommandOptionChoice, error) {
+	var choices []types.ApplicationCommandOptionChoice
+	for _, entry := range strings.Split(raw, "|") {
+		name, value, found := strings.Cut(entry, ":")
+		if !found {
+			value = name
+		}
+
+		choice := types.ApplicationCommandOptionChoice{Name: name}
+		switch optionType {
+		case types.ApplicationCommandOptionTypeString:
+			choice.Value = value
+		case types.ApplicationCommandOptionTypeInteger:
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid integer choice %q", value)
+			}
+			choice.Value = n
+		case types.ApplicationCommandOptionTypeNumber:
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number choice %q", value)
+			}
+			choice.Value = n
+		default:
+			return nil, fmt.Errorf("choices are only supported for string, integer and number options")
+		}
+		choices = append(choices, choice)
+	}
+	return choices, nil
+}
+
+func (s *structSpec) options() []types.ApplicationCommandOption {
+	options := make([]types.ApplicationCommandOption, len(s.fields))
+	for i, field := range s.fields {
+		options[i] = field.option
+	}
+	return options
+}
+
+// optionSource reads options by name, from a slash command or a hybrid command's context
+type optionSource interface {
+	String(name string) (string, bool)
+	Int(name string) (int64, bool)
+	Float(name string) (float64, bool)
+	Bool(name string) (bool, bool)
+	User(name string) (*types.User, bool)
+	Member(name string) (*types.GuildMember, bool)
+	Role(name string) (*types.Role, bool)
+	Channel(name string) (*types.Channel, bool)
+	Attachment(name string) (*types.Attachment, bool)
+	Mentionable(name string) (*Mentionable, bool)
+	Err() error
+}
+
+// decode fills dst from the context's options, skipping options that weren't given
+func (s *structSpec) decode(ctx optionSource, dst reflect.Value) error {
+	for _, field := range s.fields {
+		value, ok := decodeOption(ctx, field)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !ok {
+			// a user who isn't in the guild (or isn't cached, for prefix
+			// commands) has no member to fill the field with
+			if field.kind == memberType {
+				if user, given := ctx.User(field.option.Name); given {
+					return &ArgumentError{Name: field.option.Name, Value: user.Username, Reason: "is not a known member"}
+				}
+			}
+			continue
+		}
+
+		if err := checkFits(value, field); err != nil {
+			return err
+		}
+
+		target := dst.Field(field.index)
+		if field.ptr {
+			ptr := reflect.New(field.kind)
+			ptr.Elem().Set(value.Convert(field.kind))
+			target.Set(ptr)
+		} else {
+			target.Set(value.Convert(field.kind))
+		}
+	}
+	return nil
+}
+
+// checkFits reports an integer option that doesn't fit its field, which
+// Convert would silently wrap
+func checkFits(value reflect.Value, field fieldSpec) error {
+	if value.Kind() != reflect.Int64 {
+		return nil
+	}
+
+	n := value.Int()
+	zero := reflect.Zero(field.kind)
+	switch field.kind.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !zero.OverflowInt(n) {
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n >= 0 && !zero.OverflowUint(uint64(n)) {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return fmt.Errorf("option %q: %d doesn't fit in %s", field.option.Name, n, field.kind)
+}
+
+func decodeOption(ctx optionSource, field fieldSpec) (reflect.Value, bool) {
+	name := field.option.Name
+
+	switch field.kind {
+	case userType:
+		if user, ok := ctx.User(name); ok {
+			return reflect.ValueOf(*user), true
+		}
+		return reflect.Value{}, false
+	case memberType:
+		if member, ok := ctx.Member(name); ok {
+			return reflect.ValueOf(*member), true
+		}
+		return reflect.Value{}, false
+	case roleType:
+		if role, ok := ctx.Role(name); ok {
+			return reflect.ValueOf(*role), true
+		}
+		return reflect.Value{}, false
+	case channelType:
+		if channel, ok := ctx.Channel(name); ok {
+			return reflect.ValueOf(*channel), true
+		}
+		return reflect.Value{}, false
+	case attachmentType:
+		if attachment, ok := ctx.Attachment(name); ok {
+			return reflect.ValueOf(*attachment), true
+		}
+		return reflect.Value{}, false
+	case mentionableType:
+		if mentionable, ok := ctx.Mentionable(name); ok {
+			return reflect.ValueOf(*mentionable), true
+		}
+		return reflect.Value{}, false
+	}
+
+	switch field.option.Type {
+	case types.ApplicationCommandOptionTypeString:
+		value, ok := ctx.String(name)
+		return reflect.ValueOf(value), ok
+	case types.ApplicationCommandOptionTypeInteger:
+		value, ok := ctx.Int(name)
+		return reflect.ValueOf(value), ok
+	case types.ApplicationCommandOptionTypeNumber:
+		value, ok := ctx.Float(name)
+		return reflect.ValueOf(value), ok
+	case types.ApplicationCommandOptionTypeBoolean:
+		value, ok := ctx.Bool(name)
+		return reflect.ValueOf(value), ok
+	}
+
+	return reflect.Value{}, false
+}
+
+// snakeCase turns a Go field name like MaxUses into max_uses
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,156 @@
+package bot
+
+import (
+	"strings"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// commandRoute describes the definition generated for a subcommand path
+type commandRoute struct {
+	path        []string // command, [group,] subcommand
+	description string
+	options     []types.ApplicationCommandOption
+}
+
+type RouteOption func(*commandRoute)
+
+// WithDescription sets the description of the routed subcommand.
+// Without it the subcommand name is used.
+func WithDescription(description string) RouteOption {
+	return func(route *commandRoute) {
+		route.description = description
+	}
+}
+
+// WithOptions sets the options the routed subcommand takes
+func WithOptions(options ...types.ApplicationCommandOption) RouteOption {
+	return func(route *commandRoute) {
+		route.options = options
+	}
+}
+
+// lookupCommand finds the handler for a path, falling back to the closest
+// parent so a handler on "config" still receives "config set prefix".
+// Callers must hold commandMutex.
+func (ih *InteractionHandler) lookupCommand(path string) (CommandHandler, bool) {
+	for {
+		if handler, exists := ih.commands[path]; exists {
+			return handler, true
+		}
+
+		i := strings.LastIndex(path, " ")
+		if i < 0 {
+			return nil, false
+		}
+		path = path[:i]
+	}
+}
+
+// addRoute stores a route, replacing an earlier one for the same path.
+// Callers must hold commandMutex.
+func (ih *InteractionHandler) addRoute(route *commandRoute) {
+	path := strings.Join(route.path, " ")
+	for i, existing := range ih.routes {
+		if strings.Join(existing.path, " ") == path {
+			ih.routes[i] = route
+			return
+		}
+	}
+	ih.routes = append(ih.routes, route)
+}
+
+// applyRoutes returns a copy of command with the options of every route under
+// it merged in. Callers must hold commandMutex.
+func (ih *InteractionHandler) applyRoutes(command types.ApplicationCommand) types.ApplicationCommand {
+	if keyOf(command).commandType != types.ApplicationCommandTypeChatInput {
+		return command
+	}
+
+	command.Options = cloneOptions(command.Options)
+	for _, route := range ih.routes {
+		if route.path[0] == command.Name {
+			command.Options = mergeRoute(command.Options, route)
+		}
+	}
+	return command
+}
+
+// applyRouteToRegistered updates already registered definitions so the next
+// SyncCommands pushes the new subcommand. Callers must hold commandMutex.
+func (ih *InteractionHandler) applyRouteToRegistered(route *commandRoute) {
+	key := commandKey{name: route.path[0], commandType: types.ApplicationCommandTypeChatInput}
+	if command, exists := ih.globalCommands[key]; exists {
+		command.Options = mergeRoute(cloneOptions(command.Options), route)
+		ih.globalCommands[key] = command
+	}
+
+	for _, commands := range ih.guildCommands {
+		if command, exists := commands[key]; exists {
+			command.Options = mergeRoute(cloneOptions(command.Options), route)
+			commands[key] = command
+		}
+	}
+}
+
+// mergeRoute adds or updates the subcommand group / subcommand for a route
+func mergeRoute(options []types.ApplicationCommandOption, route *commandRoute) []types.ApplicationCommandOption {
+	if len(route.path) == 3 {
+		group := findOption(options, route.path[1])
+		if group == nil {
+			options = append(options, types.ApplicationCommandOption{
+				Type:        types.ApplicationCommandOptionTypeSubCommandGroup,
+				Name:        route.path[1],
+				Description: route.path[1],
+			})
+			group = &options[len(options)-1]
+		}
+		group.Options = mergeSubCommand(group.Options, route.path[2], route)
+		return options
+	}
+
+	return mergeSubCommand(options, route.path[1], route)
+}
+
+func mergeSubCommand(options []types.ApplicationCommandOption, name string, route *commandRoute) []types.ApplicationCommandOption {
+	sub := findOption(options, name)
+	if sub == nil {
+		options = append(options, types.ApplicationCommandOption{
+			Type:        types.ApplicationCommandOptionTypeSubCommand,
+			Name:        name,
+			Description: name,
+		})
+		sub = &options[len(options)-1]
+	}
+
+	if route.description != "" {
+		sub.Description = route.description
+	}
+	if route.options != nil {
+		sub.Options = route.options
+	}
+
+	return options
+}
+
+func findOption(options []types.ApplicationCommandOption, name string) *types.ApplicationCommandOption {
+	for i := range options {
+		if options[i].Name == name {
+			return &options[i]
+		}
+	}
+	return nil
+}
+
+func cloneOptions(options []types.ApplicationCommandOption) []types.ApplicationCommandOption {
+	if options == nil {
+		return nil
+	}
+
+	cloned := make([]types.ApplicationCommandOption, len(options))
+	for i, option := range options {
+		option.Options = cloneOptions(option.Options)
+		cloned[i] = option
+	}
+	return cloned
+}
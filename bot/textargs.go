@@ -0,0 +1,395 @@
+package bot
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// TextCommandContext is passed to text command handlers. Arguments are read by
+// position; "quoted text" counts as one argument.
+type TextCommandContext struct {
+	*MessageContext
+	Router  *TextCommandRouter
+	Command *TextCommand
+	Prefix  string   // the prefix used, the bot's mention when it was mentioned
+	Name    string   // the name or alias used
+	Args    []string // the arguments, quotes removed
+	RawArgs string   // everything after the command name, untouched
+
+	argOffsets []int // where each argument starts in RawArgs
+}
+
+// ArgumentError describes an argument that is missing or can't be converted
+type ArgumentError struct {
+	Index  int    // zero-based
+	Name   string // the option name for hybrid commands
+	Value  string
+	Reason string
+}
+
+func (e *ArgumentError) Error() string {
+	label := fmt.Sprintf("argument %d", e.Index+1)
+	if e.Name != "" {
+		label = e.Name
+	}
+	if e.Value == "" {
+		return fmt.Sprintf("%s %s", label, e.Reason)
+	}
+	return fmt.Sprintf("%s: %q %s", label, e.Value, e.Reason)
+}
+
+// displayPrefix is the prefix to show in usage and help texts
+func (ctx *TextCommandContext) displayPrefix() string {
+	if strings.HasPrefix(ctx.Prefix, "<@") {
+		return ctx.Prefix + " "
+	}
+	return ctx.Prefix
+}
+
+// Arg returns an argument, "" when there are fewer
+func (ctx *TextCommandContext) Arg(i int) string {
+	if i < 0 || i >= len(ctx.Args) {
+		return ""
+	}
+	return ctx.Args[i]
+}
+
+// Rest returns the raw text from argument i on, for trailing free text like a reason
+func (ctx *TextCommandContext) Rest(i int) string {
+	if i < 0 || i >= len(ctx.argOffsets) {
+		return ""
+	}
+	return strings.TrimSpace(ctx.RawArgs[ctx.argOffsets[i]:])
+}
+
+// arg returns argument i or an *ArgumentError when it's missing
+func (ctx *TextCommandContext) arg(i int) (string, error) {
+	if i < 0 || i >= len(ctx.Args) {
+		return "", &ArgumentError{Index: i, Reason: "is missing"}
+	}
+	return ctx.Args[i], nil
+}
+
+func (ctx *TextCommandContext) Int(i int) (int64, error) {
+	arg, err := ctx.arg(i)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, &ArgumentError{Index: i, Value: arg, Reason: "is not a whole number"}
+	}
+	return value, nil
+}
+
+func (ctx *TextCommandContext) Float(i int) (float64, error) {
+	arg, err := ctx.arg(i)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, &ArgumentError{Index: i, Value: arg, Reason: "is not a number"}
+	}
+	return value, nil
+}
+
+// Duration reads a duration like "10m", "1h30m", "2d" or "1w", see ParseDuration
+func (ctx *TextCommandContext) Duration(i int) (time.Duration, error) {
+	arg, err := ctx.arg(i)
+	if err != nil {
+		return 0, err
+	}
+	value, err := ParseDuration(arg)
+	if err != nil {
+		return 0, &ArgumentError{Index: i, Value: arg, Reason: "is not a duration like 10m, 2h or 1d"}
+	}
+	return value, nil
+}
+
+// User reads a user from a mention, an ID or, in guilds, a username or
+// nickname. Users are looked up in the message's mentions and the cache.
+func (ctx *TextCommandContext) User(i int) (*types.User, error) {
+	arg, err := ctx.arg(i)
+	if err != nil {
+		return nil, err
+	}
+
+	if id, ok := parseID(arg, ParseUserMention); ok {
+		for _, user := range ctx.Message.Mentions {
+			if user.ID == id {
+				return &user, nil
+			}
+		}
+		if user, exists := ctx.Bot.gateway.Cache().User(id); exists {
+			return &user, nil
+		}
+		return nil, &ArgumentError{Index: i, Value: arg, Reason: "is not a known user"}
+	}
+
+	if member, ok := ctx.findMember(arg); ok {
+		return &member.User, nil
+	}
+	return nil, &ArgumentError{Index: i, Value: arg, Reason: "is not a known user"}
+}
+
+// Member reads a member of the message's guild like User does
+func (ctx *TextCommandContext) Member(i int) (*types.GuildMember, error) {
+	arg, err := ctx.arg(i)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Message.GuildID == "" {
+		return nil, &ArgumentError{Index: i, Value: arg, Reason: "can't be a member outside a server"}
+	}
+
+	if id, ok := parseID(arg, ParseUserMention); ok {
+		if member, exists := ctx.Bot.gateway.Cache().Member(ctx.Message.GuildID, id); exists {
+			return &member, nil
+		}
+		return nil, &ArgumentError{Index: i, Value: arg, Reason: "is not a known member"}
+	}
+
+	if member, ok := ctx.findMember(arg); ok {
+		return &member, nil
+	}
+	return nil, &ArgumentError{Index: i, Value: arg, Reason: "is not a known member"}
+}
+
+// Channel reads a channel from a mention, an ID or a name in the message's guild
+func (ctx *TextCommandContext) Channel(i int) (*types.Channel, error) {
+	arg, err := ctx.arg(i)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := ctx.Bot.gateway.Cache()
+	if id, ok := parseID(arg, ParseChannelMention); ok {
+		if channel, exists := cache.Channel(id); exists {
+			return &channel, nil
+		}
+		return nil, &ArgumentError{Index: i, Value: arg, Reason: "is not a known channel"}
+	}
+
+	if guild, exists := cache.Guild(ctx.Message.GuildID); exists {
+		name := strings.TrimPrefix(arg, "#")
+		for _, channel := range guild.Channels {
+			if strings.EqualFold(channel.Name, name) {
+				return &channel, nil
+			}
+		}
+	}
+	return nil, &ArgumentError{Index: i, Value: arg, Reason: "is not a known channel"}
+}
+
+// Role reads a role from a mention, an ID or a name in the message's guild
+func (ctx *TextCommandContext) Role(i int) (*types.Role, error) {
+	arg, err := ctx.arg(i)
+	if err != nil {
+		return nil, err
+	}
+
+	guild, exists := ctx.Bot.gateway.Cache().Guild(ctx.Message.GuildID)
+	if !exists {
+		return nil, &ArgumentError{Index: i, Value: arg, Reason: "is not a known role"}
+	}
+
+	id, isID := parseID(arg, ParseRoleMention)
+	name := strings.TrimPrefix(arg, "@")
+	for _, role := range guild.Roles {
+		if (isID && role.ID == id) || (!isID && strings.EqualFold(role.Name, name)) {
+			return &role, nil
+		}
+	}
+	return nil, &ArgumentError{Index: i, Value: arg, Reason: "is not a known role"}
+}
+
+// findMember looks a member of the message's guild up by username,
+// nickname or "name#1234"
+func (ctx *TextCommandContext) findMember(name string) (types.GuildMember, bool) {
+	guild, exists := ctx.Bot.gateway.Cache().Guild(ctx.Message.GuildID)
+	if !exists {
+		return types.GuildMember{}, false
+	}
+
+	name = strings.TrimPrefix(name, "@")
+	for _, member := range guild.Members {
+		user := member.User
+		if strings.EqualFold(user.Username, name) || strings.EqualFold(member.Nickname, name) ||
+			strings.EqualFold(user.Username+"#"+user.Discriminator, name) {
+			return member, true
+		}
+	}
+	return types.GuildMember{}, false
+}
+
+// parseID returns the ID in a mention or a plain ID
+func parseID(arg string, parseMention func(string) (string, bool)) (string, bool) {
+	if id, ok := parseMention(arg); ok {
+		return id, true
+	}
+	if isSnowflake(arg) {
+		return arg, true
+	}
+	return "", false
+}
+
+func isSnowflake(s string) bool {
+	if len(s) < 15 || len(s) > 21 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func parseMention(s, start string) (string, bool) {
+	if !strings.HasPrefix(s, start) || !strings.HasSuffix(s, ">") {
+		return "", false
+	}
+	id := s[len(start) : len(s)-1]
+	return id, isSnowflake(id)
+}
+
+// ParseUserMention returns the user ID in <@id> or <@!id>
+func ParseUserMention(s string) (string, bool) {
+	if id, ok := parseMention(s, "<@!"); ok {
+		return id, true
+	}
+	return parseMention(s, "<@")
+}
+
+// ParseChannelMention returns the channel ID in <#id>
+func ParseChannelMention(s string) (string, bool) {
+	return parseMention(s, "<#")
+}
+
+// ParseRoleMention returns the role ID in <@&id>
+func ParseRoleMention(s string) (string, bool) {
+	return parseMention(s, "<@&")
+}
+
+var durationUnits = map[string]time.Duration{
+	"ms": time.Millisecond,
+	"s":  time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+}
+
+// ParseDuration is time.ParseDuration with days and weeks, and with units
+// written out: "1w", "2d12h", "1.5h", "30 minutes".
+func ParseDuration(s string) (time.Duration, error) {
+	rest := strings.ToLower(strings.ReplaceAll(s, " ", ""))
+	if rest == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	var total time.Duration
+	for rest != "" {
+		end := strings.IndexFunc(rest, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+		if end <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		value, err := strconv.ParseFloat(rest[:end], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		rest = rest[end:]
+
+		end = strings.IndexFunc(rest, func(r rune) bool { return unicode.IsDigit(r) || r == '.' })
+		if end < 0 {
+			end = len(rest)
+		}
+		unit, exists := durationUnits[rest[:end]]
+		if !exists {
+			return 0, fmt.Errorf("unknown unit %q in duration %q", rest[:end], s)
+		}
+		rest = rest[end:]
+
+		term := value * float64(unit)
+		if term >= math.MaxInt64 || float64(total)+term >= math.MaxInt64 {
+			return 0, fmt.Errorf("duration %q is too long", s)
+		}
+		total += time.Duration(term)
+	}
+	return total, nil
+}
+
+// splitArgs splits text at whitespace, keeping "quoted text" together. A
+// backslash escapes a quote. It also returns where each argument starts.
+func splitArgs(text string) ([]string, []int) {
+	var args []string
+	var offsets []int
+	var current strings.Builder
+	inArg, quoted := false, false
+	var quote rune
+
+	end := func() {
+		if inArg {
+			args = append(args, current.String())
+			current.Reset()
+			inArg = false
+		}
+	}
+
+	runes := []rune(text)
+	offset := 0
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		position := offset
+		offset += len(string(r))
+
+		switch {
+		case r == '\\' && i+1 < len(runes) && isQuote(runes[i+1]):
+			if !inArg {
+				inArg = true
+				offsets = append(offsets, position)
+			}
+			i++
+			offset += len(string(runes[i]))
+			current.WriteRune(runes[i])
+		case quoted && closesQuote(quote, r):
+			quoted = false
+			end()
+		case quoted:
+			current.WriteRune(r)
+		case unicode.IsSpace(r):
+			end()
+		case !inArg && isQuote(r):
+			inArg, quoted, quote = true, true, r
+			offsets = append(offsets, position)
+		default:
+			if !inArg {
+				inArg = true
+				offsets = append(offsets, position)
+			}
+			current.WriteRune(r)
+		}
+	}
+	end()
+
+	return args, offsets
+}
+
+func isQuote(r rune) bool {
+	return r == '"' || r == '\'' || r == '“'
+}
+
+// closesQuote tells whether r ends a quote opened with quote; phones type “curly quotes”
+func closesQuote(quote, r rune) bool {
+	if quote == '“' {
+		return r == '”' || r == '"'
+	}
+	return r == quote
+}
@@ -0,0 +1,132 @@
+package bot
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"500ms", 500 * time.Millisecond},
+		{"30s", 30 * time.Second},
+		{"30 minutes", 30 * time.Minute},
+		{"2hrs", 2 * time.Hour},
+		{"1w", 7 * 24 * time.Hour},
+		{"2d12h", 60 * time.Hour},
+		{"1.5h", 90 * time.Minute},
+		{".5d", 12 * time.Hour},
+		{"1H 30M", 90 * time.Minute},
+		{"106751d", 106751 * 24 * time.Hour},
+	}
+	for _, test := range tests {
+		got, err := ParseDuration(test.input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q): %v", test.input, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestParseDurationErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"h",
+		"10",
+		"10y",
+		"1.2.3h",
+		"-5m",
+		"106752d",
+		"1000000w",
+		"106751d1d",
+	} {
+		if got, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) = %v, want an error", input, got)
+		}
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		input   string
+		args    []string
+		offsets []int
+	}{
+		{"", nil, nil},
+		{"  one   two ", []string{"one", "two"}, []int{2, 8}},
+		{`ban "some user" spam`, []string{"ban", "some user", "spam"}, []int{0, 4, 16}},
+		{`say 'it works'`, []string{"say", "it works"}, []int{0, 4}},
+		{`say \"hi\"`, []string{"say", `"hi"`}, []int{0, 4}},
+		{`"a \" b"`, []string{`a " b`}, []int{0}},
+		{"“curly quotes” x", []string{"curly quotes", "x"}, []int{0, 19}},
+		{"“mixed\" x", []string{"mixed", "x"}, []int{0, 10}},
+		{`"unclosed quote`, []string{"unclosed quote"}, []int{0}},
+		{`""`, []string{""}, []int{0}},
+	}
+	for _, test := range tests {
+		args, offsets := splitArgs(test.input)
+		if !reflect.DeepEqual(args, test.args) {
+			t.Errorf("splitArgs(%q) args = %q, want %q", test.input, args, test.args)
+		}
+		if !reflect.DeepEqual(offsets, test.offsets) {
+			t.Errorf("splitArgs(%q) offsets = %v, want %v", test.input, offsets, test.offsets)
+		}
+	}
+}
+
+func TestRest(t *testing.T) {
+	raw := `@user  "spam bot"  posting   links here `
+	args, offsets := splitArgs(raw)
+	ctx := &TextCommandContext{Args: args, RawArgs: raw, argOffsets: offsets}
+
+	tests := map[int]string{
+		-1: "",
+		0:  `@user  "spam bot"  posting   links here`,
+		1:  `"spam bot"  posting   links here`,
+		2:  "posting   links here",
+		4:  "here",
+		5:  "",
+	}
+	for i, want := range tests {
+		if got := ctx.Rest(i); got != want {
+			t.Errorf("Rest(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestParseMention(t *testing.T) {
+	const id = "123456789012345678"
+	tests := []struct {
+		parse func(string) (string, bool)
+		input string
+		ok    bool
+	}{
+		{ParseUserMention, "<@" + id + ">", true},
+		{ParseUserMention, "<@!" + id + ">", true},
+		{ParseUserMention, "<@&" + id + ">", false},
+		{ParseUserMention, "<@" + id, false},
+		{ParseUserMention, "<@12345>", false},
+		{ParseUserMention, "<@abc456789012345678>", false},
+		{ParseUserMention, id, false},
+		{ParseChannelMention, "<#" + id + ">", true},
+		{ParseChannelMention, "<@" + id + ">", false},
+		{ParseRoleMention, "<@&" + id + ">", true},
+		{ParseRoleMention, "<@" + id + ">", false},
+	}
+	for _, test := range tests {
+		got, ok := test.parse(test.input)
+		if ok != test.ok {
+			t.Errorf("parsing %q: ok = %v, want %v", test.input, ok, test.ok)
+			continue
+		}
+		if ok && got != id {
+			t.Errorf("parsing %q = %q, want %q", test.input, got, id)
+		}
+	}
+}
@@ -0,0 +1,423 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/nyrilol/discord-go/api/types"
+	"github.com/nyrilol/discord-go/gateway"
+)
+
+// TextCommandHandler handles a prefix command. Returning an *ArgumentError
+// replies with the problem and the command's usage; other errors are logged.
+type TextCommandHandler func(ctx *TextCommandContext) error
+
+// TextCommand is a command invoked by a message like "!ban @user spam"
+type TextCommand struct {
+	Name        string
+	Aliases     []string
+	Description string
+	Usage       string // the arguments, e.g. "<user> [reason...]"
+	Category    string
+	Hidden      bool // left out of the help
+	GuildOnly   bool
+	Handler     TextCommandHandler
+}
+
+// TextCommandOption configures a text command
+type TextCommandOption func(*TextCommand)
+
+func WithAliases(aliases ...string) TextCommandOption {
+	return func(c *TextCommand) { c.Aliases = append(c.Aliases, aliases...) }
+}
+
+func WithTextDescription(description string) TextCommandOption {
+	return func(c *TextCommand) { c.Description = description }
+}
+
+func WithUsage(usage string) TextCommandOption {
+	return func(c *TextCommand) { c.Usage = usage }
+}
+
+func WithCategory(category string) TextCommandOption {
+	return func(c *TextCommand) { c.Category = category }
+}
+
+func WithHidden() TextCommandOption {
+	return func(c *TextCommand) { c.Hidden = true }
+}
+
+func WithGuildOnly() TextCommandOption {
+	return func(c *TextCommand) { c.GuildOnly = true }
+}
+
+// TextCommandRouter routes messages starting with a prefix to text commands.
+// Besides the prefixes, mentioning the bot works as a prefix too.
+//
+//	router := bot.NewTextCommandRouter(b, "!")
+//	router.SetGuildPrefixes(guildID, "?")
+//	router.Command("ban", func(ctx *bot.TextCommandContext) error {
+//		member, err := ctx.Member(0)
+//		if err != nil {
+//			return err
+//		}
+//		reason := ctx.Rest(1)
+//		...
+//	}, bot.WithUsage("<member> [reason...]"), bot.WithGuildOnly())
+type TextCommandRouter struct {
+	MentionPrefix   bool // accept "@bot command", on by default
+	IgnoreBots      bool // ignore messages from bots, on by default
+	CaseInsensitive bool // match command names regardless of case, on by default; set it before adding commands
+
+	bot          *Bot
+	subscription *gateway.Subscription
+
+	mu            sync.RWMutex
+	commands      []*TextCommand
+	names         map[string]*TextCommand // names and aliases
+	prefixes      []string
+	guildPrefixes map[string][]string
+	prefixFunc    func(guildID string) []string
+}
+
+// NewTextCommandRouter starts routing messages to text commands. It comes with
+// a help command, remove it with Remove("help").
+func NewTextCommandRouter(bot *Bot, prefixes ...string) *TextCommandRouter {
+	r := &TextCommandRouter{
+		MentionPrefix:   true,
+		IgnoreBots:      true,
+		CaseInsensitive: true,
+		bot:             bot,
+		names:           make(map[string]*TextCommand),
+		prefixes:        prefixes,
+		guildPrefixes:   make(map[string][]string),
+	}
+
+	r.Command("help", r.handleHelp,
+		WithTextDescription("Lists the commands or explains one"),
+		WithUsage("[command]"),
+		WithCategory("General"))
+
+	r.subscription = bot.On("MESSAGE_CREATE", r.handleMessage, types.Message{})
+	return r
+}
+
+// Command registers a text command, replacing any command with the same name
+func (r *TextCommandRouter) Command(name string, handler TextCommandHandler, opts ...TextCommandOption) *TextCommand {
+	command := &TextCommand{Name: name, Handler: handler}
+	for _, opt := range opts {
+		opt(command)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.removeLocked(name)
+	r.commands = append(r.commands, command)
+	for _, key := range append([]string{name}, command.Aliases...) {
+		r.names[r.key(key)] = command
+	}
+	return command
+}
+
+// Remove unregisters a text command by its name
+func (r *TextCommandRouter) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.removeLocked(name)
+}
+
+func (r *TextCommandRouter) removeLocked(name string) {
+	for i, command := range r.commands {
+		if r.key(command.Name) != r.key(name) {
+			continue
+		}
+		r.commands = append(r.commands[:i:i], r.commands[i+1:]...)
+		for key, c := range r.names {
+			if c == command {
+				delete(r.names, key)
+			}
+		}
+		return
+	}
+}
+
+// Lookup finds a command by its name or one of its aliases
+func (r *TextCommandRouter) Lookup(name string) (*TextCommand, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	command, exists := r.names[r.key(name)]
+	return command, exists
+}
+
+// Commands returns the commands in the order they were registered
+func (r *TextCommandRouter) Commands() []*TextCommand {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]*TextCommand(nil), r.commands...)
+}
+
+// Close stops routing messages
+func (r *TextCommandRouter) Close() {
+	r.subscription.Remove()
+}
+
+func (r *TextCommandRouter) key(name string) string {
+	if r.CaseInsensitive {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// SetPrefixes sets the prefixes used where no guild prefixes are set
+func (r *TextCommandRouter) SetPrefixes(prefixes ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prefixes = prefixes
+}
+
+// SetGuildPrefixes sets the prefixes of one guild, no prefixes restores the default ones
+func (r *TextCommandRouter) SetGuildPrefixes(guildID string, prefixes ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(prefixes) == 0 {
+		delete(r.guildPrefixes, guildID)
+		return
+	}
+	r.guildPrefixes[guildID] = prefixes
+}
+
+// SetPrefixFunc looks prefixes up with fn, e.g. from a database, instead of
+// SetGuildPrefixes. guildID is empty in DMs; returning no prefixes falls back
+// to the default ones.
+func (r *TextCommandRouter) SetPrefixFunc(fn func(guildID string) []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prefixFunc = fn
+}
+
+// Prefixes returns the prefixes that work in a guild, guildID is "" for DMs
+func (r *TextCommandRouter) Prefixes(guildID string) []string {
+	r.mu.RLock()
+	fn := r.prefixFunc
+	prefixes := r.guildPrefixes[guildID]
+	defaults := r.prefixes
+	r.mu.RUnlock()
+
+	if fn != nil {
+		prefixes = fn(guildID)
+	}
+	if len(prefixes) == 0 {
+		prefixes = defaults
+	}
+	return prefixes
+}
+
+// matchPrefix returns the prefix content starts with, trying the longest first
+// so "!!" wins over "!"
+func (r *TextCommandRouter) matchPrefix(guildID, content string) (string, bool) {
+	if r.MentionPrefix {
+		if self := r.bot.SelfID(); self != "" {
+			for _, mention := range []string{"<@" + self + ">", "<@!" + self + ">"} {
+				if strings.HasPrefix(content, mention) {
+					return mention, true
+				}
+			}
+		}
+	}
+
+	prefixes := append([]string(nil), r.Prefixes(guildID)...)
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(content, prefix) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+func (r *TextCommandRouter) handleMessage(message types.Message) {
+	if r.IgnoreBots && message.Author.Bot {
+		return
+	}
+
+	prefix, ok := r.matchPrefix(message.GuildID, message.Content)
+	if !ok {
+		return
+	}
+
+	rest := strings.TrimLeftFunc(message.Content[len(prefix):], unicode.IsSpace)
+	name := rest
+	if end := strings.IndexFunc(rest, unicode.IsSpace); end >= 0 {
+		name = rest[:end]
+	}
+	if name == "" {
+		return
+	}
+
+	command, exists := r.Lookup(name)
+	if !exists {
+		return
+	}
+
+	ctx := &TextCommandContext{
+		MessageContext: &MessageContext{Message: &message, Bot: r.bot},
+		Router:         r,
+		Command:        command,
+		Prefix:         prefix,
+		Name:           name,
+		RawArgs:        strings.TrimLeftFunc(rest[len(name):], unicode.IsSpace),
+	}
+	ctx.Args, ctx.argOffsets = splitArgs(ctx.RawArgs)
+
+	if command.GuildOnly && message.GuildID == "" {
+		r.reply(ctx, "This command only works in servers.")
+		return
+	}
+
+	err := runTextCommand(ctx)
+	if err == nil {
+		return
+	}
+
+	var argErr *ArgumentError
+	if errors.As(err, &argErr) {
+		r.reply(ctx, fmt.Sprintf("%s\nUsage: `%s`", capitalize(argErr.Error()), r.usage(command, ctx.displayPrefix())))
+		return
+	}
+
+	var panicErr *gateway.PanicError
+	if errors.As(err, &panicErr) {
+		r.bot.logger.Errorf("Text command %s failed: %v\n%s", command.Name, err, panicErr.Stack)
+		return
+	}
+	r.bot.logger.Errorf("Text command %s failed: %v", command.Name, err)
+}
+
+func runTextCommand(ctx *TextCommandContext) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &gateway.PanicError{Event: "text command " + ctx.Command.Name, Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return ctx.Command.Handler(ctx)
+}
+
+func (r *TextCommandRouter) reply(ctx *TextCommandContext, content string) {
+	if _, err := ctx.Reply(content); err != nil {
+		r.bot.logger.Errorf("Failed to reply to text command %s: %v", ctx.Command.Name, err)
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// usage returns how to call a command, e.g. "!ban <member> [reason...]"
+func (r *TextCommandRouter) usage(command *TextCommand, prefix string) string {
+	if command.Usage == "" {
+		return prefix + command.Name
+	}
+	return prefix + command.Name + " " + command.Usage
+}
+
+// Help returns the help as embeds: every visible command by category, or the
+// details of one command when name is given. ok is false for unknown commands.
+func (r *TextCommandRouter) Help(prefix, name string) (embeds []*types.Embed, ok bool) {
+	if name != "" {
+		command, exists := r.Lookup(name)
+		if !exists || command.Hidden {
+			return nil, false
+		}
+
+		embed := &types.Embed{
+			Title:       prefix + command.Name,
+			Description: command.Description,
+			Color:       ColorBlurple,
+			Fields:      []types.EmbedField{{Name: "Usage", Value: "`" + r.usage(command, prefix) + "`"}},
+		}
+		if len(command.Aliases) > 0 {
+			embed.Fields = append(embed.Fields, types.EmbedField{Name: "Aliases", Value: strings.Join(command.Aliases, ", ")})
+		}
+		return []*types.Embed{embed}, true
+	}
+
+	categories := map[string][]string{}
+	var order []string
+	for _, command := range r.Commands() {
+		if command.Hidden {
+			continue
+		}
+		category := command.Category
+		if category == "" {
+			category = "Other"
+		}
+		if _, exists := categories[category]; !exists {
+			order = append(order, category)
+		}
+
+		line := "`" + prefix + command.Name + "`"
+		if command.Description != "" {
+			line += " - " + command.Description
+		}
+		categories[category] = append(categories[category], line)
+	}
+
+	var description strings.Builder
+	for i, category := range order {
+		if i > 0 {
+			description.WriteString("\n")
+		}
+		description.WriteString("**" + category + "**\n")
+		description.WriteString(strings.Join(categories[category], "\n"))
+		description.WriteString("\n")
+	}
+
+	embed := types.Embed{Title: "Commands", Color: ColorBlurple}
+	embed.Footer = &types.EmbedFooter{Text: fmt.Sprintf("Use %shelp <command> for details", prefix)}
+	return SplitEmbed(embed, description.String()), true
+}
+
+func (r *TextCommandRouter) handleHelp(ctx *TextCommandContext) error {
+	embeds, ok := r.Help(ctx.displayPrefix(), ctx.Arg(0))
+	if !ok {
+		_, err := ctx.Reply(fmt.Sprintf("There is no command called `%s`.", ctx.Arg(0)))
+		return err
+	}
+
+	for _, group := range GroupEmbeds(embeds) {
+		if _, err := ctx.ReplyMessage(types.WebhookMessage{Embeds: group}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Send sends a message to the channel of the message
+func (ctx *MessageContext) Send(content string) (*types.Message, error) {
+	return ctx.SendMessage(types.WebhookMessage{Content: content})
+}
+
+// Reply answers the message as a reply
+func (ctx *MessageContext) Reply(content string) (*types.Message, error) {
+	return ctx.ReplyMessage(types.WebhookMessage{Content: content})
+}
+
+func (ctx *MessageContext) SendMessage(message types.WebhookMessage) (*types.Message, error) {
+	return ctx.Bot.gateway.CreateMessage(ctx.Message.ChannelID, message, nil)
+}
+
+func (ctx *MessageContext) ReplyMessage(message types.WebhookMessage) (*types.Message, error) {
+	return ctx.Bot.gateway.CreateMessage(ctx.Message.ChannelID, message, &types.MessageReference{
+		MessageID: ctx.Message.ID,
+		ChannelID: ctx.Message.ChannelID,
+		GuildID:   ctx.Message.GuildID,
+	})
+}
@@ -0,0 +1,278 @@
+package gateway
+
+import (
+	"encoding/json"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// Cache returns the guilds, channels, members and roles seen on this session,
+// and the users of those members. It is kept up to date before the handlers
+// of an event run.
+func (g *Gateway) Cache() *SessionCache {
+	return g.cache
+}
+
+// Guild returns a cached guild with its roles, channels and the members seen
+// so far, in no particular order. Members are kept apart from Guilds so they
+// can be updated in place; use Member to look one up.
+func (c *SessionCache) Guild(guildID string) (types.Guild, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	guild, exists := c.Guilds[guildID]
+	if !exists {
+		return guild, false
+	}
+
+	guild.Members = make([]types.GuildMember, 0, len(c.members[guildID]))
+	for _, member := range c.members[guildID] {
+		guild.Members = append(guild.Members, member)
+	}
+	return guild, true
+}
+
+func (c *SessionCache) Channel(channelID string) (types.Channel, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	channel, exists := c.Channels[channelID]
+	return channel, exists
+}
+
+func (c *SessionCache) User(userID string) (types.User, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	user, exists := c.Users[userID]
+	return user, exists
+}
+
+func (c *SessionCache) Member(guildID, userID string) (types.GuildMember, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	member, exists := c.members[guildID][userID]
+	return member, exists
+}
+
+func (c *SessionCache) Role(guildID, roleID string) (types.Role, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, role := range c.Guilds[guildID].Roles {
+		if role.ID == roleID {
+			return role, true
+		}
+	}
+	return types.Role{}, false
+}
+
+// updateCache applies an event to the cache. The slices of a cached guild are
+// replaced rather than modified, so guilds handed out earlier stay unchanged.
+// A user is cached while they're a member of a cached guild.
+func (g *Gateway) updateCache(eventType string, data json.RawMessage) {
+	c := g.cache
+
+	switch eventType {
+	case "GUILD_CREATE", "GUILD_UPDATE":
+		var guild types.Guild
+		if err := json.Unmarshal(data, &guild); err != nil {
+			return
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		// updates don't carry channels and members
+		if old, exists := c.Guilds[guild.ID]; exists && guild.Channels == nil {
+			guild.Channels = old.Channels
+		}
+		for i := range guild.Channels {
+			guild.Channels[i].GuildID = guild.ID
+			c.Channels[guild.Channels[i].ID] = guild.Channels[i]
+		}
+		members := guild.Members
+		guild.Members = nil
+		c.Guilds[guild.ID] = guild
+		if c.members[guild.ID] == nil {
+			c.members[guild.ID] = make(map[string]types.GuildMember, len(members))
+		}
+		for _, member := range members {
+			c.Users[member.User.ID] = member.User
+			c.upsertMemberLocked(guild.ID, member)
+		}
+
+	case "GUILD_DELETE":
+		var event types.GuildDeleteEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return
+		}
+
+		// an outage makes guilds unavailable for a while; keep them for when they return
+		if event.Unavailable {
+			return
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, channel := range c.Guilds[event.ID].Channels {
+			delete(c.Channels, channel.ID)
+		}
+		members := c.members[event.ID]
+		delete(c.Guilds, event.ID)
+		delete(c.members, event.ID)
+		for userID := range members {
+			c.forgetUserLocked(userID)
+		}
+
+	case "CHANNEL_CREATE", "CHANNEL_UPDATE", "CHANNEL_DELETE":
+		var channel types.Channel
+		if err := json.Unmarshal(data, &channel); err != nil {
+			return
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		deleted := eventType == "CHANNEL_DELETE"
+		if deleted {
+			delete(c.Channels, channel.ID)
+		} else {
+			c.Channels[channel.ID] = channel
+		}
+		if guild, exists := c.Guilds[channel.GuildID]; exists {
+			guild.Channels = replaceByID(guild.Channels, channel, func(ch types.Channel) string { return ch.ID }, deleted)
+			c.Guilds[guild.ID] = guild
+		}
+
+	case "GUILD_MEMBER_ADD", "GUILD_MEMBER_UPDATE":
+		var event struct {
+			GuildID string `json:"guild_id"`
+			types.GuildMember
+		}
+		if err := json.Unmarshal(data, &event); err != nil {
+			return
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if _, exists := c.Guilds[event.GuildID]; exists {
+			c.Users[event.User.ID] = event.User
+		}
+		c.upsertMemberLocked(event.GuildID, event.GuildMember)
+
+	case "GUILD_MEMBER_REMOVE":
+		var event types.GuildMemberRemoveEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if members, exists := c.members[event.GuildID]; exists {
+			delete(members, event.User.ID)
+			c.forgetUserLocked(event.User.ID)
+		}
+
+	case "GUILD_ROLE_CREATE", "GUILD_ROLE_UPDATE":
+		var event types.GuildRoleCreateEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if guild, exists := c.Guilds[event.GuildID]; exists {
+			guild.Roles = replaceByID(guild.Roles, event.Role, roleID, false)
+			c.Guilds[guild.ID] = guild
+		}
+
+	case "GUILD_ROLE_DELETE":
+		var event types.GuildRoleDeleteEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if guild, exists := c.Guilds[event.GuildID]; exists {
+			guild.Roles = replaceByID(guild.Roles, types.Role{ID: event.RoleID}, roleID, true)
+			c.Guilds[guild.ID] = guild
+		}
+
+	case "MESSAGE_CREATE":
+		var message types.Message
+		if err := json.Unmarshal(data, &message); err != nil {
+			return
+		}
+
+		// only members of cached guilds are added, so DMs and busy channels
+		// don't grow the cache; users already cached are kept up to date
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if _, exists := c.Guilds[message.GuildID]; exists && message.Member != nil {
+			c.Users[message.Author.ID] = message.Author
+			member := *message.Member
+			member.User = message.Author
+			c.upsertMemberLocked(message.GuildID, member)
+		} else {
+			c.refreshUserLocked(message.Author)
+		}
+		for _, user := range message.Mentions {
+			c.refreshUserLocked(user)
+		}
+	}
+}
+
+// upsertMemberLocked adds a member to its guild or updates it, keeping the
+// fields the event didn't send
+func (c *SessionCache) upsertMemberLocked(guildID string, member types.GuildMember) {
+	members, exists := c.members[guildID]
+	if !exists {
+		return
+	}
+
+	if old, exists := members[member.User.ID]; exists {
+		if member.JoinedAt == "" {
+			member.JoinedAt = old.JoinedAt
+		}
+		if member.Roles == nil {
+			member.Roles = old.Roles
+		}
+	}
+	members[member.User.ID] = member
+}
+
+// refreshUserLocked updates a user that is already cached
+func (c *SessionCache) refreshUserLocked(user types.User) {
+	if _, exists := c.Users[user.ID]; exists {
+		c.Users[user.ID] = user
+	}
+}
+
+// forgetUserLocked removes a user that isn't a member of any cached guild anymore
+func (c *SessionCache) forgetUserLocked(userID string) {
+	for _, members := range c.members {
+		if _, exists := members[userID]; exists {
+			return
+		}
+	}
+	delete(c.Users, userID)
+}
+
+func roleID(role types.Role) string { return role.ID }
+
+// replaceByID returns a copy of items with item replaced (or appended), or
+// removed when remove is set
+func replaceByID[T any](items []T, item T, id func(T) string, remove bool) []T {
+	result := make([]T, 0, len(items)+1)
+	found := false
+	for _, existing := range items {
+		if id(existing) != id(item) {
+			result = append(result, existing)
+			continue
+		}
+		found = true
+		if !remove {
+			result = append(result, item)
+		}
+	}
+	if !found && !remove {
+		result = append(result, item)
+	}
+	return result
+}
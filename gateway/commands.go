@@ -0,0 +1,229 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// Application Command Management -----------------------------------------------
+//
+// Every function takes a guild ID; an empty one means the global scope.
+
+func (g *Gateway) commandsURL(guildID types.Snowflake) (string, error) {
+	appID, err := g.getApplicationID()
+	if err != nil {
+		g.logger.Errorf("failed to get application ID: %v", err)
+
+		return "", err
+	}
+
+	if guildID != "" {
+		return fmt.Sprintf("https://discord.com/api/v10/applications/%s/guilds/%s/commands", appID, guildID), nil
+	}
+	return fmt.Sprintf("https://discord.com/api/v10/applications/%s/commands", appID), nil
+}
+
+// GetApplicationCommands lists the registered commands for a scope. withLocalizations
+// returns the full localization maps instead of just the localized name/description.
+func (g *Gateway) GetApplicationCommands(guildID types.Snowflake, withLocalizations bool) ([]types.ApplicationCommand, error) {
+	url, err := g.commandsURL(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	if withLocalizations {
+		url += "?with_localizations=true"
+	}
+
+	resp, err := g.makeHTTPRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var commands []types.ApplicationCommand
+	if err := json.Unmarshal(resp, &commands); err != nil {
+		return nil, err
+	}
+
+	return commands, nil
+}
+
+func (g *Gateway) GetApplicationCommand(guildID, commandID types.Snowflake) (*types.ApplicationCommand, error) {
+	url, err := g.commandsURL(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.makeHTTPRequest("GET", url+"/"+commandID.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var command types.ApplicationCommand
+	if err := json.Unmarshal(resp, &command); err != nil {
+		return nil, err
+	}
+
+	return &command, nil
+}
+
+// UpsertApplicationCommand POSTs a command. Discord overwrites an existing command
+// with the same name and type, so this is safe to call for existing commands.
+func (g *Gateway) UpsertApplicationCommand(guildID types.Snowflake, command types.ApplicationCommand) (*types.ApplicationCommand, error) {
+	url, err := g.commandsURL(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(command)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.makeHTTPRequest("POST", url, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var created types.ApplicationCommand
+	if err := json.Unmarshal(resp, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (g *Gateway) EditApplicationCommand(guildID, commandID types.Snowflake, command types.ApplicationCommand) (*types.ApplicationCommand, error) {
+	url, err := g.commandsURL(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(command)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.makeHTTPRequest("PATCH", url+"/"+commandID.String(), data)
+	if err != nil {
+		return nil, err
+	}
+
+	var edited types.ApplicationCommand
+	if err := json.Unmarshal(resp, &edited); err != nil {
+		return nil, err
+	}
+
+	return &edited, nil
+}
+
+func (g *Gateway) DeleteApplicationCommand(guildID, commandID types.Snowflake) error {
+	url, err := g.commandsURL(guildID)
+	if err != nil {
+		return err
+	}
+
+	_, err = g.makeHTTPRequest("DELETE", url+"/"+commandID.String(), nil)
+	return err
+}
+
+// BulkOverwriteApplicationCommands replaces every command in the scope with the given list.
+// Commands that aren't in the list are deleted.
+func (g *Gateway) BulkOverwriteApplicationCommands(guildID types.Snowflake, commands []types.ApplicationCommand) ([]types.ApplicationCommand, error) {
+	url, err := g.commandsURL(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	if commands == nil {
+		commands = []types.ApplicationCommand{}
+	}
+
+	data, err := json.Marshal(commands)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.makeHTTPRequest("PUT", url, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var overwritten []types.ApplicationCommand
+	if err := json.Unmarshal(resp, &overwritten); err != nil {
+		return nil, err
+	}
+
+	return overwritten, nil
+}
+
+// Command Permissions ----------------------------------------------------------
+
+func (g *Gateway) GetGuildApplicationCommandPermissions(guildID types.Snowflake) ([]types.GuildApplicationCommandPermissions, error) {
+	url, err := g.commandsURL(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.makeHTTPRequest("GET", url+"/permissions", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []types.GuildApplicationCommandPermissions
+	if err := json.Unmarshal(resp, &permissions); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func (g *Gateway) GetApplicationCommandPermissions(guildID, commandID types.Snowflake) (*types.GuildApplicationCommandPermissions, error) {
+	url, err := g.commandsURL(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.makeHTTPRequest("GET", fmt.Sprintf("%s/%s/permissions", url, commandID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions types.GuildApplicationCommandPermissions
+	if err := json.Unmarshal(resp, &permissions); err != nil {
+		return nil, err
+	}
+
+	return &permissions, nil
+}
+
+// EditApplicationCommandPermissions overwrites the permissions of a command in a guild.
+// Discord only accepts a Bearer token with the applications.commands.permissions.update
+// scope here, so bearerToken must be an OAuth2 access token, not the bot token.
+func (g *Gateway) EditApplicationCommandPermissions(guildID, commandID types.Snowflake, bearerToken string, permissions []types.ApplicationCommandPermissions) (*types.GuildApplicationCommandPermissions, error) {
+	url, err := g.commandsURL(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"permissions": permissions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.makeAuthorizedHTTPRequest("PUT", fmt.Sprintf("%s/%s/permissions", url, commandID), data, "Bearer "+bearerToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated types.GuildApplicationCommandPermissions
+	if err := json.Unmarshal(resp, &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
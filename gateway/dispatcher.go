@@ -0,0 +1,405 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// DispatchMode decides where event handlers run
+type DispatchMode int
+
+const (
+	// DispatchSync runs handlers on the read loop, one event after another in
+	// the order they arrived. A slow handler holds up every event after it.
+	DispatchSync DispatchMode = iota
+	// DispatchWorkerPool runs handlers on a fixed number of workers fed by bounded queues
+	DispatchWorkerPool
+	// DispatchGoroutine starts a goroutine for every event
+	DispatchGoroutine
+)
+
+// DispatchOrdering decides which events are handled one after another
+type DispatchOrdering int
+
+const (
+	// OrderNone handles events in any order
+	OrderNone DispatchOrdering = iota
+	// OrderPerGuild handles the events of a guild in the order they arrived
+	OrderPerGuild
+	// OrderPerChannel handles the events of a channel in the order they arrived
+	OrderPerChannel
+)
+
+// BackpressurePolicy decides what happens to an event when its queue is full
+type BackpressurePolicy int
+
+const (
+	// BackpressureBlock waits for room, which stops reading from the gateway meanwhile
+	BackpressureBlock BackpressurePolicy = iota
+	// BackpressureDropNewest drops the incoming event
+	BackpressureDropNewest
+	// BackpressureDropOldest drops the longest waiting event to make room
+	BackpressureDropOldest
+)
+
+const (
+	DefaultDispatchWorkers   = 8
+	DefaultDispatchQueueSize = 256
+)
+
+// DispatcherConfig configures how events reach their handlers. The zero value
+// handles events synchronously and in order; concurrency is opt-in through
+// Mode. Workers and QueueSize default to DefaultDispatchWorkers and
+// DefaultDispatchQueueSize.
+//
+// With an ordering, events sharing a guild or channel are handled one at a time
+// in the order they arrived, while the others still run concurrently. Events
+// without a guild or channel (READY, DMs in OrderPerGuild, ...) are never held back.
+type DispatcherConfig struct {
+	Mode         DispatchMode
+	Workers      int // worker pool size
+	QueueSize    int // events waiting per queue, zero for the default
+	Ordering     DispatchOrdering
+	Backpressure BackpressurePolicy
+}
+
+// SetDispatcher changes how events are dispatched. Events already queued are
+// still handled by the previous dispatcher.
+func (g *Gateway) SetDispatcher(config DispatcherConfig) {
+	g.mu.Lock()
+	if g.dispatcherClosed {
+		g.dispatchConfig = config
+		g.mu.Unlock()
+		return
+	}
+	old := g.dispatcher
+	g.dispatchConfig = config
+	g.dispatcher = nil
+	g.mu.Unlock()
+
+	if old != nil {
+		old.stop()
+	}
+}
+
+// getDispatcher returns the current dispatcher, starting it on first use. It
+// returns nil once the gateway is closed, so late events are dropped instead
+// of starting new workers.
+func (g *Gateway) getDispatcher() *dispatcher {
+	g.mu.RLock()
+	d, closed := g.dispatcher, g.dispatcherClosed
+	g.mu.RUnlock()
+	if d != nil || closed {
+		return d
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.dispatcher == nil && !g.dispatcherClosed {
+		g.dispatcher = newDispatcher(g, g.dispatchConfig)
+	}
+	return g.dispatcher
+}
+
+// stopDispatcher tells the workers to exit once they've handled what's queued,
+// without waiting for them, so a handler may close the gateway. No dispatcher
+// is started after it.
+func (g *Gateway) stopDispatcher() {
+	g.mu.Lock()
+	d := g.dispatcher
+	g.dispatcher = nil
+	g.dispatcherClosed = true
+	g.mu.Unlock()
+
+	if d != nil {
+		d.stop()
+	}
+}
+
+type dispatchEvent struct {
+	eventType string
+	data      json.RawMessage
+}
+
+type dispatcher struct {
+	gateway *Gateway
+	config  DispatcherConfig
+
+	// worker pool: every worker reads the shared queue and its own queue,
+	// events with an ordering key always go to the same worker
+	shared  chan dispatchEvent
+	workers []chan dispatchEvent
+
+	// goroutine mode with ordering: events waiting per key
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending map[string][]dispatchEvent
+
+	// stop closes done; workers then wait for the senders still handing them
+	// events before emptying their queues and exiting
+	closeMu sync.Mutex
+	closed  bool
+	done    chan struct{}
+	senders sync.WaitGroup
+	dropped atomic.Uint64
+}
+
+func newDispatcher(g *Gateway, config DispatcherConfig) *dispatcher {
+	if config.Workers <= 0 {
+		config.Workers = DefaultDispatchWorkers
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultDispatchQueueSize
+	}
+
+	d := &dispatcher{gateway: g, config: config, done: make(chan struct{})}
+	d.cond = sync.NewCond(&d.mu)
+
+	switch config.Mode {
+	case DispatchWorkerPool:
+		d.shared = make(chan dispatchEvent, config.QueueSize)
+		if config.Ordering != OrderNone {
+			d.workers = make([]chan dispatchEvent, config.Workers)
+		}
+		for i := 0; i < config.Workers; i++ {
+			var own chan dispatchEvent
+			if d.workers != nil {
+				own = make(chan dispatchEvent, config.QueueSize)
+				d.workers[i] = own
+			}
+			go d.work(own)
+		}
+	case DispatchGoroutine:
+		d.pending = make(map[string][]dispatchEvent)
+	}
+
+	return d
+}
+
+func (d *dispatcher) dispatch(eventType string, data json.RawMessage) {
+	event := dispatchEvent{eventType: eventType, data: data}
+
+	if d.config.Mode == DispatchSync {
+		d.run(event)
+		return
+	}
+
+	if !d.accept(event) {
+		// the dispatcher was replaced (or the gateway closed) while this event was read
+		if next := d.gateway.getDispatcher(); next != nil {
+			next.dispatch(eventType, data)
+		}
+	}
+}
+
+// accept hands an event to the workers, or returns false when the dispatcher
+// was stopped before it could.
+func (d *dispatcher) accept(event dispatchEvent) bool {
+	d.closeMu.Lock()
+	if d.closed {
+		d.closeMu.Unlock()
+		return false
+	}
+	d.senders.Add(1)
+	d.closeMu.Unlock()
+	defer d.senders.Done()
+
+	key := ""
+	if d.config.Ordering != OrderNone {
+		key = orderingKey(d.config.Ordering, event.eventType, event.data)
+	}
+
+	if d.config.Mode == DispatchGoroutine {
+		if key == "" {
+			go d.run(event)
+			return true
+		}
+		return d.enqueueKeyed(key, event)
+	}
+
+	queue := d.shared
+	if key != "" {
+		h := fnv.New32a()
+		h.Write([]byte(key))
+		queue = d.workers[h.Sum32()%uint32(len(d.workers))]
+	}
+	return d.enqueue(queue, event)
+}
+
+// enqueue puts an event on a worker queue, applying the backpressure policy.
+// It returns false when the dispatcher stopped while waiting for room.
+func (d *dispatcher) enqueue(queue chan dispatchEvent, event dispatchEvent) bool {
+	switch d.config.Backpressure {
+	case BackpressureDropNewest:
+		select {
+		case queue <- event:
+		default:
+			d.drop(event)
+		}
+	case BackpressureDropOldest:
+		for {
+			select {
+			case queue <- event:
+				return true
+			default:
+			}
+			select {
+			case old := <-queue:
+				d.drop(old)
+			default:
+			}
+		}
+	default:
+		select {
+		case queue <- event:
+		case <-d.done:
+			return false
+		}
+	}
+	return true
+}
+
+func (d *dispatcher) work(own chan dispatchEvent) {
+	for {
+		select {
+		case event := <-d.shared:
+			d.run(event)
+		case event := <-own:
+			d.run(event)
+		case <-d.done:
+			d.senders.Wait()
+			for {
+				select {
+				case event := <-d.shared:
+					d.run(event)
+				case event := <-own:
+					d.run(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// enqueueKeyed queues an event behind the others of its key, starting a
+// goroutine for the key when nothing of it is running. It returns false when
+// the dispatcher stopped while waiting for room.
+func (d *dispatcher) enqueueKeyed(key string, event dispatchEvent) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	queue, running := d.pending[key]
+	if len(queue) >= d.config.QueueSize {
+		switch d.config.Backpressure {
+		case BackpressureDropNewest:
+			d.drop(event)
+			return true
+		case BackpressureDropOldest:
+			d.drop(queue[0])
+			queue = queue[1:]
+		default:
+			for len(d.pending[key]) >= d.config.QueueSize {
+				select {
+				case <-d.done:
+					return false
+				default:
+				}
+				d.cond.Wait()
+			}
+			queue, running = d.pending[key]
+		}
+	}
+
+	d.pending[key] = append(queue, event)
+	if !running {
+		go d.drain(key)
+	}
+	return true
+}
+
+func (d *dispatcher) drain(key string) {
+	for {
+		d.mu.Lock()
+		queue := d.pending[key]
+		if len(queue) == 0 {
+			delete(d.pending, key)
+			d.mu.Unlock()
+			return
+		}
+		event := queue[0]
+		d.pending[key] = queue[1:]
+		d.cond.Broadcast()
+		d.mu.Unlock()
+
+		d.run(event)
+	}
+}
+
+func (d *dispatcher) drop(event dispatchEvent) {
+	n := d.dropped.Add(1)
+	d.gateway.logger.Warnf("Event queue full, dropped %s event (%d dropped so far)", event.eventType, n)
+}
+
+// run handles one event, logging what its handlers and middlewares returned
+// and recovering from panics in middlewares
+func (d *dispatcher) run(event dispatchEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.gateway.logger.Errorf("Panic while dispatching %s: %v\n%s", event.eventType, r, debug.Stack())
+		}
+	}()
+
+	err := d.gateway.handleEvent(event.eventType, event.data)
+	if err == nil {
+		return
+	}
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		d.gateway.logger.Errorf("Error handling %s: %v\n%s", event.eventType, err, panicErr.Stack)
+		return
+	}
+	d.gateway.logger.Errorf("Error handling %s: %v", event.eventType, err)
+}
+
+// stop tells the workers to exit; queued events are still handled
+func (d *dispatcher) stop() {
+	d.closeMu.Lock()
+	if d.closed {
+		d.closeMu.Unlock()
+		return
+	}
+	d.closed = true
+	close(d.done)
+	d.closeMu.Unlock()
+
+	// wake senders waiting for room in a keyed queue
+	d.mu.Lock()
+	d.cond.Broadcast()
+	d.mu.Unlock()
+}
+
+// orderingKey returns the guild or channel an event belongs to, or "" when it has none
+func orderingKey(ordering DispatchOrdering, eventType string, data json.RawMessage) string {
+	var ids eventIDs
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return ""
+	}
+
+	switch ordering {
+	case OrderPerGuild:
+		if id := eventGuildID(eventType, ids); id != "" {
+			return "g" + id
+		}
+	case OrderPerChannel:
+		if id := eventChannelID(eventType, ids); id != "" {
+			return "c" + id
+		}
+	}
+	return ""
+}
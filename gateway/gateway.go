@@ -12,7 +12,10 @@ import (
 	"math"
 	"net/http"
 	"reflect"
+	"runtime/debug"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -36,13 +39,17 @@ const (
 type Gateway struct {
 	Token         string
 	Conn          *websocket.Conn
-	EventChan     chan json.RawMessage
 	stopHeartbeat chan struct{}
 	cache         *SessionCache
 	eventHandlers map[string][]eventHandler
-	middlewares   []MiddlewareFunc
+	nextHandlerID HandlerID
+	middlewares   []middleware
 	logger        utils.Logger
 
+	dispatcher       *dispatcher // started on the first event, see SetDispatcher
+	dispatchConfig   DispatcherConfig
+	dispatcherClosed bool // set by Close; no dispatcher is started after it
+
 	// protected by mutex
 	mu                sync.RWMutex
 	sequence          *int64
@@ -51,13 +58,53 @@ type Gateway struct {
 	state             ConnectionState
 	reconnectAttempts int
 	heartbeatInterval time.Duration
+	applicationID     string
+	shard             [2]int
 }
 
 type eventHandler struct {
+	id          HandlerID
 	handlerFunc interface{}
 	eventType   reflect.Type
+	once        *atomic.Bool // set for once-handlers, true after the first call
+
+	decode func(data json.RawMessage) (interface{}, error) // returns a pointer to a new eventType
+	call   func(event interface{}) error                   // gets what decode returned
+}
+
+// HandlerID identifies a single registered handler, see RemoveHandlerByID
+type HandlerID uint64
+
+// Subscription is returned when registering a handler and removes just that handler
+type Subscription struct {
+	gateway   *Gateway
+	eventType string
+	id        HandlerID
+}
+
+func (s *Subscription) ID() HandlerID {
+	return s.id
 }
 
+func (s *Subscription) Event() string {
+	return s.eventType
+}
+
+// Remove unregisters the handler. It reports whether it was still registered.
+func (s *Subscription) Remove() bool {
+	return s.gateway.RemoveHandlerByID(s.eventType, s.id)
+}
+
+// HandlerInfo describes a registered handler, see Handlers
+type HandlerInfo struct {
+	ID        HandlerID
+	Event     string
+	EventType reflect.Type // type the event is decoded into
+	Once      bool
+}
+
+// MiddlewareFunc sees the raw payload of an event, see Use. UseMiddleware
+// takes the richer EventMiddleware.
 type MiddlewareFunc func(eventType string, data json.RawMessage, next func())
 
 type SessionCache struct {
@@ -65,6 +112,7 @@ type SessionCache struct {
 	Channels map[string]types.Channel
 	Users    map[string]types.User
 	Messages map[string]types.Message
+	members  map[string]map[string]types.GuildMember // by guild ID, then user ID
 	mu       sync.RWMutex
 }
 
@@ -76,10 +124,10 @@ func NewGateway(token string, intents ...int) *Gateway {
 
 	return &Gateway{
 		Token:         token,
-		EventChan:     make(chan json.RawMessage, 100),
 		stopHeartbeat: make(chan struct{}),
 		intents:       intentValue,
 		state:         StateDisconnected,
+		shard:         [2]int{0, 1},
 		eventHandlers: make(map[string][]eventHandler),
 		logger:        utils.NewLogger(),
 		cache: &SessionCache{
@@ -87,6 +135,7 @@ func NewGateway(token string, intents ...int) *Gateway {
 			Channels: make(map[string]types.Channel),
 			Users:    make(map[string]types.User),
 			Messages: make(map[string]types.Message),
+			members:  make(map[string]map[string]types.GuildMember),
 		},
 	}
 }
@@ -94,12 +143,6 @@ func NewGateway(token string, intents ...int) *Gateway {
 // Connection Management --------------------------------------------------------
 
 func (g *Gateway) Connect(url string) error {
-	g.mu.Lock()
-	if g.EventChan == nil {
-		g.EventChan = make(chan json.RawMessage, 100)
-	}
-	g.mu.Unlock()
-
 	g.setState(StateConnecting)
 
 	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
@@ -120,17 +163,14 @@ func (g *Gateway) Connect(url string) error {
 }
 
 func (g *Gateway) Close() error {
+	g.stopDispatcher()
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
 	g.setState(StateDisconnected)
 	close(g.stopHeartbeat)
 
-	if g.EventChan != nil {
-		close(g.EventChan)
-		g.EventChan = nil
-	}
-
 	if g.Conn != nil {
 		return g.Conn.Close()
 	}
@@ -139,14 +179,26 @@ func (g *Gateway) Close() error {
 
 // Event Handling --------------------------------------------------------------
 
-func (g *Gateway) RegisterHandler(eventType string, handlerFunc interface{}, eventStruct ...interface{}) {
-	g.mu.Lock()
-	defer g.mu.Unlock()
+// RegisterHandler adds a handler for an event. The event is decoded into
+// eventStruct's type if given, otherwise into the handler's parameter type.
+func (g *Gateway) RegisterHandler(eventType string, handlerFunc interface{}, eventStruct ...interface{}) *Subscription {
+	return g.registerHandler(eventType, handlerFunc, false, eventStruct)
+}
 
+// RegisterOnceHandler adds a handler that is removed after its first call
+func (g *Gateway) RegisterOnceHandler(eventType string, handlerFunc interface{}, eventStruct ...interface{}) *Subscription {
+	return g.registerHandler(eventType, handlerFunc, true, eventStruct)
+}
+
+func (g *Gateway) registerHandler(eventType string, handlerFunc interface{}, once bool, eventStruct []interface{}) *Subscription {
 	handlerType := reflect.TypeOf(handlerFunc)
 	if handlerType.Kind() != reflect.Func || handlerType.NumIn() != 1 {
 		panic("handler must be a function with exactly one parameter")
 	}
+	returnsError := handlerType.NumOut() == 1 && handlerType.Out(0) == errorType
+	if handlerType.NumOut() > 0 && !returnsError {
+		panic("handler can only return an error")
+	}
 
 	var eventTypeReflect reflect.Type
 	if len(eventStruct) > 0 {
@@ -155,79 +207,198 @@ func (g *Gateway) RegisterHandler(eventType string, handlerFunc interface{}, eve
 		eventTypeReflect = handlerType.In(0)
 	}
 
-	g.eventHandlers[eventType] = append(g.eventHandlers[eventType], eventHandler{
+	fn := reflect.ValueOf(handlerFunc)
+	return g.addHandler(eventType, eventHandler{
 		handlerFunc: handlerFunc,
 		eventType:   eventTypeReflect,
-	})
+		decode: func(data json.RawMessage) (interface{}, error) {
+			eventPtr := reflect.New(eventTypeReflect).Interface()
+			err := json.Unmarshal(data, eventPtr)
+			return eventPtr, err
+		},
+		call: func(event interface{}) error {
+			out := fn.Call([]reflect.Value{reflect.ValueOf(event).Elem()})
+			if returnsError && !out[0].IsNil() {
+				return out[0].Interface().(error)
+			}
+			return nil
+		},
+	}, once)
 }
 
+// addHandler assigns an ID to a handler and registers it. Callers must not hold mu.
+func (g *Gateway) addHandler(eventType string, handler eventHandler, once bool) *Subscription {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	handler.id = g.nextHandlerID + 1
+	if once {
+		handler.once = &atomic.Bool{}
+	}
+
+	g.nextHandlerID++
+	g.eventHandlers[eventType] = append(g.eventHandlers[eventType], handler)
+	return &Subscription{gateway: g, eventType: eventType, id: handler.id}
+}
+
+// RemoveHandler removes every handler of an event, use Subscription.Remove to remove one
 func (g *Gateway) RemoveHandler(eventType string) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 	delete(g.eventHandlers, eventType)
 }
 
+// RemoveHandlerByID removes one handler and leaves the others for the event alone.
+// It reports whether the handler was still registered.
+func (g *Gateway) RemoveHandlerByID(eventType string, id HandlerID) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	handlers := g.eventHandlers[eventType]
+	for i, handler := range handlers {
+		if handler.id != id {
+			continue
+		}
+		// copy so a dispatch iterating the old slice isn't affected
+		remaining := make([]eventHandler, 0, len(handlers)-1)
+		remaining = append(remaining, handlers[:i]...)
+		remaining = append(remaining, handlers[i+1:]...)
+		if len(remaining) == 0 {
+			delete(g.eventHandlers, eventType)
+		} else {
+			g.eventHandlers[eventType] = remaining
+		}
+		return true
+	}
+	return false
+}
+
+// Handlers lists the registered handlers in registration order, for all
+// events or only the given ones.
+func (g *Gateway) Handlers(eventTypes ...string) []HandlerInfo {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if len(eventTypes) == 0 {
+		for eventType := range g.eventHandlers {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	var infos []HandlerInfo
+	for _, eventType := range eventTypes {
+		for _, handler := range g.eventHandlers[eventType] {
+			infos = append(infos, HandlerInfo{
+				ID:        handler.id,
+				Event:     eventType,
+				EventType: handler.eventType,
+				Once:      handler.once != nil,
+			})
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// Use adds a middleware that sees the raw payload of every event
 func (g *Gateway) Use(middleware MiddlewareFunc) {
+	g.UseMiddleware(func(ctx *EventContext, next func() error) error {
+		var err error
+		middleware(ctx.EventType, ctx.Data, func() { err = next() })
+		return err
+	})
+}
+
+// UseMiddleware adds a middleware for the given event types, or for every
+// event when none are given. Middlewares run in the order they were added.
+func (g *Gateway) UseMiddleware(fn EventMiddleware, eventTypes ...string) {
+	mw := middleware{fn: fn}
+	if len(eventTypes) > 0 {
+		mw.eventTypes = make(map[string]bool, len(eventTypes))
+		for _, eventType := range eventTypes {
+			mw.eventTypes[eventType] = true
+		}
+	}
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
-	g.middlewares = append(g.middlewares, middleware)
+	g.middlewares = append(g.middlewares, mw)
 }
 
-func (g *Gateway) handleEvent(eventType string, data json.RawMessage) {
+// handleEvent runs an event through the middlewares and its handlers and
+// returns what went wrong, handler errors and panics included
+func (g *Gateway) handleEvent(eventType string, data json.RawMessage) error {
 	g.mu.RLock()
 	handlers := g.eventHandlers[eventType]
 	middlewares := g.middlewares
+	shard := g.shard
 	g.mu.RUnlock()
 
 	if len(handlers) == 0 {
-		return
+		return nil
 	}
 
-<<<<<<< HEAD
-	//   middleware chain
-=======
-	// create 	 middleware chain
->>>>>>> b641ce9 (should be ok)
-	chain := func() {
+	ctx := &EventContext{
+		EventType: eventType,
+		Data:      data,
+		Gateway:   g,
+		Shard:     shard,
+		handlers:  handlers,
+	}
+
+	chain := func() error {
+		var errs []error
 		for _, handler := range handlers {
-			eventPtr := reflect.New(handler.eventType).Interface()
-			if err := json.Unmarshal(data, eventPtr); err != nil {
-				g.logger.Errorf("Failed to unmarshal %s event: %v", eventType, err)
+			if handler.once != nil {
+				if handler.once.Swap(true) {
+					continue
+				}
+				g.RemoveHandlerByID(eventType, handler.id)
+			}
+
+			// the payload is decoded once per event type and shared by all
+			// handlers (and middlewares) asking for that type
+			event, err := decodeShared(&ctx.decoded, handler.eventType, handler.decode, data)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to unmarshal %s event: %w", eventType, err))
 				continue
 			}
 
-			reflect.ValueOf(handler.handlerFunc).Call([]reflect.Value{
-				reflect.ValueOf(eventPtr).Elem(),
-			})
+			if err := g.callHandler(eventType, handler, event); err != nil {
+				errs = append(errs, err)
+			}
 		}
+		return errors.Join(errs...)
 	}
 
-<<<<<<< HEAD
 	// apply middlewares in reverse order
-=======
-	// reverse order
->>>>>>> b641ce9 (should be ok)
 	for i := len(middlewares) - 1; i >= 0; i-- {
 		mw := middlewares[i]
-		prevChain := chain
-		chain = func() { mw(eventType, data, prevChain) }
+		if mw.eventTypes != nil && !mw.eventTypes[eventType] {
+			continue
+		}
+		next := chain
+		chain = func() error { return mw.fn(ctx, next) }
 	}
 
-	chain()
+	return chain()
 }
 
-// WebSocket Communication -----------------------------------------------------
-
-func (g *Gateway) listen() {
+// callHandler runs a handler, turning a panic into a *PanicError so the
+// other handlers of the event still run
+func (g *Gateway) callHandler(eventType string, handler eventHandler, event interface{}) (err error) {
 	defer func() {
-		g.mu.Lock()
-		defer g.mu.Unlock()
-		if g.EventChan != nil {
-			close(g.EventChan)
-			g.EventChan = nil
+		if r := recover(); r != nil {
+			err = &PanicError{Event: eventType, Value: r, Stack: debug.Stack()}
 		}
 	}()
+	return handler.call(event)
+}
+
+// WebSocket Communication -----------------------------------------------------
 
+func (g *Gateway) listen() {
 	for {
 		_, message, err := g.Conn.ReadMessage()
 		if err != nil {
@@ -256,7 +427,13 @@ func (g *Gateway) listen() {
 
 		switch baseEvent.OP {
 		case 0: // Dispatch
-			g.handleEvent(baseEvent.T, baseEvent.D)
+			if baseEvent.T == "READY" {
+				g.handleReady(baseEvent.D)
+			}
+			g.updateCache(baseEvent.T, baseEvent.D)
+			if d := g.getDispatcher(); d != nil {
+				d.dispatch(baseEvent.T, baseEvent.D)
+			}
 		case 10: // Hello
 			g.handleHello(baseEvent.D)
 		case 11: // Heartbeat ACK
@@ -274,6 +451,24 @@ func (g *Gateway) listen() {
 	}
 }
 
+func (g *Gateway) handleReady(data json.RawMessage) {
+	var ready struct {
+		SessionID string `json:"session_id"`
+		Shard     []int  `json:"shard"`
+	}
+	if err := json.Unmarshal(data, &ready); err != nil {
+		g.logger.Errorf("Failed to parse ready: %v", err)
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.sessionID = ready.SessionID
+	if len(ready.Shard) == 2 {
+		g.shard = [2]int{ready.Shard[0], ready.Shard[1]}
+	}
+}
+
 func (g *Gateway) handleHello(data json.RawMessage) {
 	var hello struct {
 		HeartbeatInterval int `json:"heartbeat_interval"`
@@ -419,6 +614,30 @@ func (g *Gateway) SendInteractionResponse(interactionID types.Snowflake, interac
 	return err
 }
 
+// SendAutocompleteResult answers an autocomplete interaction. Discord needs the
+// choices array even when it's empty, so this doesn't go through InteractionCallbackData.
+func (g *Gateway) SendAutocompleteResult(interactionID types.Snowflake, interactionToken string, choices []types.ApplicationCommandOptionChoice) error {
+	if choices == nil {
+		choices = []types.ApplicationCommandOptionChoice{}
+	}
+
+	payload := map[string]interface{}{
+		"type": types.InteractionResponseTypeApplicationCommandAutocompleteResult,
+		"data": map[string]interface{}{
+			"choices": choices,
+		},
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://discord.com/api/v10/interactions/%s/%s/callback", interactionID, interactionToken)
+	_, err = g.makeHTTPRequest("POST", url, data)
+	return err
+}
+
 func (g *Gateway) SendFollowupMessage(interactionToken string, message types.WebhookMessage) error {
 	appID, err := g.getApplicationID()
 	if err != nil {
@@ -498,6 +717,13 @@ func (g *Gateway) createApplicationCommand(guildID types.Snowflake, command type
 }
 
 func (g *Gateway) getApplicationID() (string, error) {
+	g.mu.RLock()
+	cached := g.applicationID
+	g.mu.RUnlock()
+	if cached != "" {
+		return cached, nil
+	}
+
 	url := "https://discord.com/api/v10/users/@me"
 	resp, err := g.makeHTTPRequest("GET", url, nil)
 	if err != nil {
@@ -511,16 +737,24 @@ func (g *Gateway) getApplicationID() (string, error) {
 		return "", err
 	}
 
+	g.mu.Lock()
+	g.applicationID = botUser.ID
+	g.mu.Unlock()
+
 	return botUser.ID, nil
 }
 
 func (g *Gateway) makeHTTPRequest(method, url string, body []byte) ([]byte, error) {
+	return g.makeAuthorizedHTTPRequest(method, url, body, "Bot "+g.Token)
+}
+
+func (g *Gateway) makeAuthorizedHTTPRequest(method, url string, body []byte, authorization string) ([]byte, error) {
 	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bot "+g.Token)
+	req.Header.Set("Authorization", authorization)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "DiscordBot (https://github.com/nyrilol/discord-go, 1.0)")
 
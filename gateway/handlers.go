@@ -0,0 +1,54 @@
+package gateway
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// AddHandler registers a typed handler without reflection. The payload is
+// decoded once per event and the same *T is passed to every handler of that
+// type, so handlers must not modify it.
+func AddHandler[T any](g *Gateway, eventType string, handler func(*T)) *Subscription {
+	return g.addHandler(eventType, typedHandler(handler), false)
+}
+
+// AddOnceHandler is AddHandler for a handler that is removed after its first call
+func AddOnceHandler[T any](g *Gateway, eventType string, handler func(*T)) *Subscription {
+	return g.addHandler(eventType, typedHandler(handler), true)
+}
+
+func typedHandler[T any](handler func(*T)) eventHandler {
+	return eventHandler{
+		handlerFunc: handler,
+		eventType:   reflect.TypeOf((*T)(nil)).Elem(),
+		decode: func(data json.RawMessage) (interface{}, error) {
+			event := new(T)
+			err := json.Unmarshal(data, event)
+			return event, err
+		},
+		call: func(event interface{}) error {
+			handler(event.(*T))
+			return nil
+		},
+	}
+}
+
+type decodedEvent struct {
+	eventType reflect.Type
+	value     interface{}
+	err       error
+}
+
+// decodeShared returns the payload decoded into eventType, decoding it only
+// the first time the type is asked for.
+func decodeShared(decoded *[]decodedEvent, eventType reflect.Type, decode func(json.RawMessage) (interface{}, error), data json.RawMessage) (interface{}, error) {
+	for _, d := range *decoded {
+		if d.eventType == eventType {
+			return d.value, d.err
+		}
+	}
+
+	value, err := decode(data)
+	*decoded = append(*decoded, decodedEvent{eventType: eventType, value: value, err: err})
+	return value, err
+}
@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+var benchMessage = json.RawMessage(`{
+	"id": "1100000000000000000",
+	"channel_id": "1100000000000000001",
+	"guild_id": "1100000000000000002",
+	"content": "hello there, this is a message of average length",
+	"timestamp": "2024-01-01T00:00:00.000000+00:00",
+	"author": {"id": "1100000000000000003", "username": "someone", "discriminator": "0"},
+	"mentions": [],
+	"attachments": [],
+	"embeds": []
+}`)
+
+var benchSink string
+
+// BenchmarkDispatch compares typed handlers added with AddHandler to the
+// reflection-based RegisterHandler, with one and several handlers per event.
+func BenchmarkDispatch(b *testing.B) {
+	for _, handlers := range []int{1, 3} {
+		b.Run(fmt.Sprintf("AddHandler/%d", handlers), func(b *testing.B) {
+			g := NewGateway("bench")
+			for i := 0; i < handlers; i++ {
+				AddHandler(g, "MESSAGE_CREATE", func(event *types.MessageCreateEvent) {
+					benchSink = event.Content
+				})
+			}
+			benchmarkHandleEvent(b, g)
+		})
+
+		b.Run(fmt.Sprintf("RegisterHandler/%d", handlers), func(b *testing.B) {
+			g := NewGateway("bench")
+			for i := 0; i < handlers; i++ {
+				g.RegisterHandler("MESSAGE_CREATE", func(event types.MessageCreateEvent) {
+					benchSink = event.Content
+				})
+			}
+			benchmarkHandleEvent(b, g)
+		})
+	}
+}
+
+func benchmarkHandleEvent(b *testing.B, g *Gateway) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := g.handleEvent("MESSAGE_CREATE", benchMessage); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
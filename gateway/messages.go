@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nyrilol/discord-go/api/types"
+)
+
+// Channel Messages -------------------------------------------------------------
+
+// CreateMessage sends a message to a channel. reference makes it a reply and
+// may be nil. Webhook-only fields of the message (username, avatar, thread
+// name) are ignored by discord.
+func (g *Gateway) CreateMessage(channelID string, message types.WebhookMessage, reference *types.MessageReference) (*types.Message, error) {
+	payload := struct {
+		types.WebhookMessage
+		MessageReference *types.MessageReference `json:"message_reference,omitempty"`
+	}{message, reference}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("https://discord.com/api/v10/channels/%s/messages", channelID)
+	body, err := g.makeHTTPRequest("POST", url, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var created types.Message
+	if err := json.Unmarshal(body, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// EditMessage edits a message the bot sent. Only the content, embeds,
+// components, flags and allowed mentions of the message are used.
+func (g *Gateway) EditMessage(channelID, messageID string, message types.WebhookMessage) (*types.Message, error) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("https://discord.com/api/v10/channels/%s/messages/%s", channelID, messageID)
+	body, err := g.makeHTTPRequest("PATCH", url, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var edited types.Message
+	if err := json.Unmarshal(body, &edited); err != nil {
+		return nil, err
+	}
+	return &edited, nil
+}
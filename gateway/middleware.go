@@ -0,0 +1,253 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nyrilol/discord-go/utils"
+)
+
+// EventMiddleware wraps the handling of an event. Call next to continue with
+// the next middleware and finally the handlers, or return without calling it
+// to stop the event. Errors returned by next (handler errors, panics as
+// *PanicError) can be inspected, replaced or swallowed; what the outermost
+// middleware returns is logged by the dispatcher.
+//
+//	g.UseMiddleware(func(ctx *gateway.EventContext, next func() error) error {
+//		start := time.Now()
+//		err := next()
+//		log.Printf("%s took %v", ctx.EventType, time.Since(start))
+//		return err
+//	}, "MESSAGE_CREATE")
+type EventMiddleware func(ctx *EventContext, next func() error) error
+
+type middleware struct {
+	fn         EventMiddleware
+	eventTypes map[string]bool // nil for every event
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// PanicError is returned when a handler (or, with Recover, a middleware) panicked
+type PanicError struct {
+	Event string
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic while handling %s: %v", e.Event, e.Value)
+}
+
+// EventContext carries one event through its middlewares
+type EventContext struct {
+	EventType string
+	Data      json.RawMessage
+	Gateway   *Gateway // the connection (shard) that received the event
+	Shard     [2]int   // shard ID and shard count, [0, 1] without sharding
+
+	handlers []eventHandler
+	decoded  []decodedEvent
+	mu       sync.Mutex
+	values   map[string]interface{}
+}
+
+// Set stores a value for the middlewares after this one
+func (c *EventContext) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.values == nil {
+		c.values = make(map[string]interface{})
+	}
+	c.values[key] = value
+}
+
+// Get returns a value stored with Set
+func (c *EventContext) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, exists := c.values[key]
+	return value, exists
+}
+
+// Decode decodes the payload into v, which must be a pointer. The payload is
+// decoded once per type and shared with the handlers asking for the same type.
+func (c *EventContext) Decode(v interface{}) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer")
+	}
+
+	eventType := target.Type().Elem()
+	event, err := decodeShared(&c.decoded, eventType, func(data json.RawMessage) (interface{}, error) {
+		event := reflect.New(eventType).Interface()
+		err := json.Unmarshal(data, event)
+		return event, err
+	}, c.Data)
+	if err != nil {
+		return err
+	}
+
+	target.Elem().Set(reflect.ValueOf(event).Elem())
+	return nil
+}
+
+// Event returns the payload decoded into the type the first handler of the
+// event takes, as a pointer. Handlers see the same value, don't modify it.
+func (c *EventContext) Event() (interface{}, error) {
+	if len(c.handlers) == 0 {
+		return nil, fmt.Errorf("%s has no handlers", c.EventType)
+	}
+	handler := c.handlers[0]
+	return decodeShared(&c.decoded, handler.eventType, handler.decode, c.Data)
+}
+
+// GuildID returns the guild the event happened in, "" outside guilds
+func (c *EventContext) GuildID() string {
+	return eventGuildID(c.EventType, c.ids())
+}
+
+// ChannelID returns the channel the event happened in, "" when it has none
+func (c *EventContext) ChannelID() string {
+	return eventChannelID(c.EventType, c.ids())
+}
+
+func (c *EventContext) ids() eventIDs {
+	var ids eventIDs
+	c.Decode(&ids)
+	return ids
+}
+
+// eventIDs holds the fields used to tell which guild and channel an event belongs to
+type eventIDs struct {
+	ID        string `json:"id"`
+	GuildID   string `json:"guild_id"`
+	ChannelID string `json:"channel_id"`
+}
+
+// eventGuildID returns the guild of an event. Guild events carry it in "id"
+// rather than "guild_id".
+func eventGuildID(eventType string, ids eventIDs) string {
+	if ids.GuildID == "" && (eventType == "GUILD_CREATE" || eventType == "GUILD_UPDATE" || eventType == "GUILD_DELETE") {
+		return ids.ID
+	}
+	return ids.GuildID
+}
+
+// eventChannelID returns the channel of an event. Channel events carry it in
+// "id" rather than "channel_id".
+func eventChannelID(eventType string, ids eventIDs) string {
+	if ids.ChannelID == "" && (eventType == "CHANNEL_CREATE" || eventType == "CHANNEL_UPDATE" || eventType == "CHANNEL_DELETE") {
+		return ids.ID
+	}
+	return ids.ChannelID
+}
+
+// Recover turns panics in the middlewares after it into a *PanicError.
+// Handler panics are always recovered.
+func Recover() EventMiddleware {
+	return func(ctx *EventContext, next func() error) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &PanicError{Event: ctx.EventType, Value: r, Stack: debug.Stack()}
+			}
+		}()
+		return next()
+	}
+}
+
+// LogEvents logs every event with how long its handlers took and whether they failed
+func LogEvents(logger utils.Logger) EventMiddleware {
+	return func(ctx *EventContext, next func() error) error {
+		start := time.Now()
+		err := next()
+		if err != nil {
+			logger.Debugf("%s failed after %v: %v", ctx.EventType, time.Since(start), err)
+		} else {
+			logger.Debugf("%s handled in %v", ctx.EventType, time.Since(start))
+		}
+		return err
+	}
+}
+
+// EventStats are the metrics of one event type
+type EventStats struct {
+	Event    string
+	Count    int64
+	Errors   int64
+	Total    time.Duration
+	Max      time.Duration
+	LastSeen time.Time
+}
+
+// Average returns the mean handling time
+func (s EventStats) Average() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+// EventMetrics counts events and measures their handling time per event type
+//
+//	metrics := gateway.NewEventMetrics()
+//	g.UseMiddleware(metrics.Middleware())
+type EventMetrics struct {
+	mu    sync.Mutex
+	stats map[string]*EventStats
+}
+
+func NewEventMetrics() *EventMetrics {
+	return &EventMetrics{stats: make(map[string]*EventStats)}
+}
+
+func (m *EventMetrics) Middleware() EventMiddleware {
+	return func(ctx *EventContext, next func() error) error {
+		start := time.Now()
+		err := next()
+		elapsed := time.Since(start)
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		stats, exists := m.stats[ctx.EventType]
+		if !exists {
+			stats = &EventStats{Event: ctx.EventType}
+			m.stats[ctx.EventType] = stats
+		}
+		stats.Count++
+		if err != nil {
+			stats.Errors++
+		}
+		stats.Total += elapsed
+		if elapsed > stats.Max {
+			stats.Max = elapsed
+		}
+		stats.LastSeen = start
+		return err
+	}
+}
+
+// Snapshot returns the current stats sorted by event type
+func (m *EventMetrics) Snapshot() []EventStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make([]EventStats, 0, len(m.stats))
+	for _, stats := range m.stats {
+		snapshot = append(snapshot, *stats)
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Event < snapshot[j].Event })
+	return snapshot
+}
+
+// Reset clears the collected stats
+func (m *EventMetrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats = make(map[string]*EventStats)
+}
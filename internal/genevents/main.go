@@ -0,0 +1,94 @@
+// genevents generates the event name methods in api/types and the typed
+// OnXxx helpers in bot from the event structs in api/types/events.go.
+//
+// Run it with go generate from the bot package.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+)
+
+func main() {
+	eventsFile := flag.String("events", "../api/types/events.go", "file declaring the event structs")
+	typesOut := flag.String("types-out", "../api/types/event_names.go", "output for the EventName methods")
+	botOut := flag.String("bot-out", "events_gen.go", "output for the bot helpers")
+	flag.Parse()
+
+	events, err := eventTypes(*eventsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var names bytes.Buffer
+	names.WriteString("// Code generated by internal/genevents; DO NOT EDIT.\n\npackage types\n\n")
+	names.WriteString("// Event is implemented by every gateway event struct\ntype Event interface {\n\tEventName() string\n}\n")
+	for _, event := range events {
+		fmt.Fprintf(&names, "\nfunc (%s) EventName() string { return %q }\n", event, eventName(event))
+	}
+
+	var helpers bytes.Buffer
+	helpers.WriteString("// Code generated by internal/genevents; DO NOT EDIT.\n\npackage bot\n\n")
+	helpers.WriteString("import (\n\t\"github.com/nyrilol/discord-go/api/types\"\n\t\"github.com/nyrilol/discord-go/gateway\"\n)\n")
+	for _, event := range events {
+		method := strings.TrimSuffix(event, "Event")
+		fmt.Fprintf(&helpers, "\n// On%s registers a handler for %s events\n", method, eventName(event))
+		fmt.Fprintf(&helpers, "func (bot *Bot) On%s(handler func(*types.%s)) *gateway.Subscription {\n\treturn On(bot, handler)\n}\n", method, event)
+	}
+
+	for path, src := range map[string][]byte{*typesOut: names.Bytes(), *botOut: helpers.Bytes()} {
+		formatted, err := format.Source(src)
+		if err != nil {
+			log.Fatalf("%s: %v", path, err)
+		}
+		if err := os.WriteFile(path, formatted, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// eventTypes returns the struct types whose name ends in Event, in file order
+func eventTypes(path string) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if _, isStruct := typeSpec.Type.(*ast.StructType); isStruct && strings.HasSuffix(typeSpec.Name.Name, "Event") {
+				events = append(events, typeSpec.Name.Name)
+			}
+		}
+	}
+	return events, nil
+}
+
+// eventName turns MessageCreateEvent into MESSAGE_CREATE
+func eventName(typeName string) string {
+	name := strings.TrimSuffix(typeName, "Event")
+
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -158,7 +158,7 @@ func (l *BotLogger) log(level LogLevel, message string, v ...interface{}) {
 }
 
 func (l *BotLogger) Debug(v ...interface{}) {
-	l.log(LevelDebug, fmt.Sprint(v...))
+	l.log(LevelDebug, "%s", fmt.Sprint(v...))
 }
 
 func (l *BotLogger) Debugf(format string, v ...interface{}) {
@@ -166,7 +166,7 @@ func (l *BotLogger) Debugf(format string, v ...interface{}) {
 }
 
 func (l *BotLogger) Info(v ...interface{}) {
-	l.log(LevelInfo, fmt.Sprint(v...))
+	l.log(LevelInfo, "%s", fmt.Sprint(v...))
 }
 
 func (l *BotLogger) Infof(format string, v ...interface{}) {
@@ -174,7 +174,7 @@ func (l *BotLogger) Infof(format string, v ...interface{}) {
 }
 
 func (l *BotLogger) Warn(v ...interface{}) {
-	l.log(LevelWarning, fmt.Sprint(v...))
+	l.log(LevelWarning, "%s", fmt.Sprint(v...))
 }
 
 func (l *BotLogger) Warnf(format string, v ...interface{}) {
@@ -182,7 +182,7 @@ func (l *BotLogger) Warnf(format string, v ...interface{}) {
 }
 
 func (l *BotLogger) Error(v ...interface{}) {
-	l.log(LevelError, fmt.Sprint(v...))
+	l.log(LevelError, "%s", fmt.Sprint(v...))
 }
 
 func (l *BotLogger) Errorf(format string, v ...interface{}) {
@@ -190,7 +190,7 @@ func (l *BotLogger) Errorf(format string, v ...interface{}) {
 }
 
 func (l *BotLogger) Critical(v ...interface{}) {
-	l.log(LevelCritical, fmt.Sprint(v...))
+	l.log(LevelCritical, "%s", fmt.Sprint(v...))
 }
 
 func (l *BotLogger) Criticalf(format string, v ...interface{}) {